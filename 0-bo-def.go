@@ -3,7 +3,14 @@
 // ------------------------------------------------------------------------------------------------
 package goald
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	core "github.com/aldesgroup/corego"
+)
 
 // ------------------------------------------------------------------------------------------------
 // Interface for all the business objects - All the generic functions will rely on this
@@ -15,20 +22,55 @@ type IBusinessObject interface {
 	getClassName() className
 	setClassName(className)
 	GetID() BObjID
-	setID(int)
+	setID(BObjID)
 
-	// business logic
+	// business logic - all these hooks are called by the generic BLO functions, within the DB transaction, if any
 	ChangeBeforeInsert(BloContext) error
 	IsValid(BloContext) error
 	ChangeAfterInsert(BloContext) error
+	ChangeBeforeUpdate(bloCtx BloContext, previous IBusinessObject) error // previous = the BO as currently persisted
+	ChangeAfterUpdate(bloCtx BloContext, previous IBusinessObject) error  // previous = the BO as persisted before the update
+	ChangeBeforeDelete(BloContext) error
+	ChangeAfterDelete(BloContext) error
+	ChangeAfterRead(BloContext) error
 }
 
 // ------------------------------------------------------------------------------------------------
 // Common implementation for business objects - Should be part of any BO's inheritance
 // ------------------------------------------------------------------------------------------------
 
-// type BObjID string // probably a UUID here
-type BObjID int64 // probably a UUID here
+// BObjID is either an integer generated by the DB, or a UUID generated in Go, depending on the class' ID strategy
+type BObjID string
+
+// integer IDs are still passed as JSON numbers
+func (id BObjID) MarshalJSON() ([]byte, error) {
+	if _, errParse := strconv.ParseInt(string(id), 10, 64); errParse == nil {
+		return []byte(id), nil
+	}
+
+	return json.Marshal(string(id))
+}
+
+// an ID is read either from a JSON string, or from a JSON integer; anything else is an error
+func (id *BObjID) UnmarshalJSON(data []byte) error {
+	switch {
+	case string(data) == "null":
+		*id = ""
+	case len(data) > 0 && data[0] == '"':
+		var idAsString string
+		if errUnmarshal := json.Unmarshal(data, &idAsString); errUnmarshal != nil {
+			return errUnmarshal
+		}
+		*id = BObjID(idAsString)
+	default:
+		if _, errParse := strconv.ParseInt(string(data), 10, 64); errParse != nil {
+			return Error("Invalid ID %s: expecting an integer or a string", data)
+		}
+		*id = BObjID(data)
+	}
+
+	return nil
+}
 
 type BusinessObject struct {
 	specs     IBusinessObjectSpecs
@@ -51,13 +93,18 @@ func (thisBO *BusinessObject) Specs() IBusinessObjectSpecs {
 }
 
 /* default implementations */
-func (thisBO *BusinessObject) getClassName() className             { return thisBO.className }
-func (thisBO *BusinessObject) setClassName(cn className)           { thisBO.className = cn }
-func (thisBO *BusinessObject) GetID() BObjID                       { return thisBO.ID }
-func (thisBO *BusinessObject) setID(id int)                        { thisBO.ID = BObjID(id) }
-func (thisBO *BusinessObject) ChangeBeforeInsert(BloContext) error { return nil }
-func (thisBO *BusinessObject) IsValid(BloContext) error            { return nil }
-func (thisBO *BusinessObject) ChangeAfterInsert(BloContext) error  { return nil }
+func (thisBO *BusinessObject) getClassName() className                              { return thisBO.className }
+func (thisBO *BusinessObject) setClassName(cn className)                            { thisBO.className = cn }
+func (thisBO *BusinessObject) GetID() BObjID                                        { return thisBO.ID }
+func (thisBO *BusinessObject) setID(id BObjID)                                      { thisBO.ID = id }
+func (thisBO *BusinessObject) ChangeBeforeInsert(BloContext) error                  { return nil }
+func (thisBO *BusinessObject) IsValid(BloContext) error                             { return nil }
+func (thisBO *BusinessObject) ChangeAfterInsert(BloContext) error                   { return nil }
+func (thisBO *BusinessObject) ChangeBeforeUpdate(BloContext, IBusinessObject) error { return nil }
+func (thisBO *BusinessObject) ChangeAfterUpdate(BloContext, IBusinessObject) error  { return nil }
+func (thisBO *BusinessObject) ChangeBeforeDelete(BloContext) error                  { return nil }
+func (thisBO *BusinessObject) ChangeAfterDelete(BloContext) error                   { return nil }
+func (thisBO *BusinessObject) ChangeAfterRead(BloContext) error                     { return nil }
 
 // ------------------------------------------------------------------------------------------------
 // Modelling enum types
@@ -69,3 +116,38 @@ type IEnum interface {
 	Val() int
 	Values() map[int]string
 }
+
+// the separator used when writing out several enum values, e.g. "1,3"
+const enumValuesSEPARATOR = ","
+
+// EnumsToString writes out the values of the given enums, e.g. "1,3"; this is used by the value mappers
+func EnumsToString[EnumType IEnum](enums []EnumType) string {
+	return strings.Join(core.MapFn(enums, func(enum EnumType) string { return strconv.Itoa(enum.Val()) }), enumValuesSEPARATOR)
+}
+
+// StringToEnums reads enum values written like "1,3", each of them having to be listed; this is used by the value mappers
+func StringToEnums[EnumType interface {
+	IEnum
+	~int
+}](valueAsString string) ([]EnumType, error) {
+	if valueAsString == "" {
+		return nil, nil
+	}
+
+	enums := []EnumType{}
+	for _, valAsString := range strings.Split(valueAsString, enumValuesSEPARATOR) {
+		val, errConv := strconv.Atoi(valAsString)
+		if errConv != nil {
+			return nil, ErrorC(errConv, "Invalid enum value '%s'", valAsString)
+		}
+
+		enum := EnumType(val)
+		if enum.String() == "" {
+			return nil, Error("%d is not a listed value", val)
+		}
+
+		enums = append(enums, enum)
+	}
+
+	return enums, nil
+}
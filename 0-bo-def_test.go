@@ -0,0 +1,90 @@
+package goald
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBObjIDMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       BObjID
+		expected string
+	}{
+		{"integer", "12", `12`},
+		{"zero", "0", `0`},
+		{"negative integer", "-3", `-3`},
+		{"UUID", "0b7e5f2a-3c1d-4e8f-9a6b-2d4c8e1f7a90", `"0b7e5f2a-3c1d-4e8f-9a6b-2d4c8e1f7a90"`},
+		{"empty", "", `""`},
+		{"too big for an integer", "99999999999999999999", `"99999999999999999999"`},
+		{"decimal", "1.5", `"1.5"`},
+		{"with quotes", `a"b`, `"a\"b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.id)
+			if err != nil {
+				t.Fatalf("json.Marshal(%q) error = %v", tt.id, err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("json.Marshal(%q) = %s, expected %s", tt.id, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBObjIDUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected BObjID
+		wantErr  bool
+	}{
+		{"integer", `12`, "12", false},
+		{"negative integer", `-3`, "-3", false},
+		{"integer as a string", `"12"`, "12", false},
+		{"UUID", `"0b7e5f2a-3c1d-4e8f-9a6b-2d4c8e1f7a90"`, "0b7e5f2a-3c1d-4e8f-9a6b-2d4c8e1f7a90", false},
+		{"empty string", `""`, "", false},
+		{"escaped string", `"a\"b"`, `a"b`, false},
+		{"null", `null`, "", false},
+		{"decimal", `1.5`, "", true},
+		{"boolean", `true`, "", true},
+		{"array", `[12]`, "", true},
+		{"object", `{"ID":12}`, "", true},
+		{"too big for an integer", `99999999999999999999`, "", true},
+		{"unterminated string", `"12`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got BObjID
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("json.Unmarshal(%s) = %q, expected %q", tt.data, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBObjIDRoundTrip(t *testing.T) {
+	for _, id := range []BObjID{"12", "0b7e5f2a-3c1d-4e8f-9a6b-2d4c8e1f7a90", ""} {
+		t.Run(string(id), func(t *testing.T) {
+			jsonBytes, errMarshal := json.Marshal(&BusinessObject{ID: id})
+			if errMarshal != nil {
+				t.Fatalf("json.Marshal() error = %v", errMarshal)
+			}
+
+			got := &BusinessObject{}
+			if errUnmarshal := json.Unmarshal(jsonBytes, got); errUnmarshal != nil {
+				t.Fatalf("json.Unmarshal(%s) error = %v", jsonBytes, errUnmarshal)
+			}
+			if got.ID != id {
+				t.Errorf("the ID %q has been read back as %q, from %s", id, got.ID, jsonBytes)
+			}
+		})
+	}
+}
@@ -67,3 +67,90 @@ func (thisProperty PropertyType) Val() int {
 func (thisProperty PropertyType) Values() map[int]string {
 	return propertyTypes
 }
+
+// ------------------------------------------------------------------------------------------------
+// the way the IDs of a class' business objects are generated
+// ------------------------------------------------------------------------------------------------
+
+// IDStrategy tells how the IDs of a class' business objects are generated
+type IDStrategy int
+
+const (
+	// IDStrategyDEFAULT : using the app's default strategy, which is IDStrategyIDENTITY, unless changed
+	IDStrategyDEFAULT IDStrategy = iota
+
+	// IDStrategyIDENTITY : an integer auto-incremented by the DB
+	IDStrategyIDENTITY
+
+	// IDStrategyUUIDxV4 : a random UUID, generated in Go
+	IDStrategyUUIDxV4
+
+	// IDStrategyUUIDxV7 : a time-ordered UUID, generated in Go, which is friendlier with the DB indexes
+	IDStrategyUUIDxV7
+)
+
+var idStrategies = map[int]string{
+	int(IDStrategyDEFAULT):  "default",
+	int(IDStrategyIDENTITY): "identity",
+	int(IDStrategyUUIDxV4):  "UUID v4",
+	int(IDStrategyUUIDxV7):  "UUID v7",
+}
+
+func (thisStrategy IDStrategy) String() string {
+	return idStrategies[int(thisStrategy)]
+}
+
+// Val helps implement the IEnum interface
+func (thisStrategy IDStrategy) Val() int {
+	return int(thisStrategy)
+}
+
+// Values helps implement the IEnum interface
+func (thisStrategy IDStrategy) Values() map[int]string {
+	return idStrategies
+}
+
+// tells if the IDs are UUIDs generated in Go
+func (thisStrategy IDStrategy) isUUID() bool {
+	return thisStrategy == IDStrategyUUIDxV4 || thisStrategy == IDStrategyUUIDxV7
+}
+
+// ------------------------------------------------------------------------------------------------
+// the way the hierarchy of classes inheriting from an abstract class is persisted
+// ------------------------------------------------------------------------------------------------
+
+// InheritanceStrategy tells how the BOs of the classes inheriting from an abstract class are persisted
+type InheritanceStrategy int
+
+const (
+	// InheritanceStrategyNONE : each concrete class is persisted on its own, and the abstract class cannot be loaded
+	InheritanceStrategyNONE InheritanceStrategy = iota
+
+	// InheritanceStrategySINGLExTABLE : all the concrete classes share the abstract class' table,
+	// their rows being told apart by a discriminator column containing the class name
+	InheritanceStrategySINGLExTABLE
+
+	// InheritanceStrategyTABLExPERxCLASS : each concrete class has its own table, all of them being
+	// read when loading the abstract class; the IDs must be UUIDs, to be unique across the tables
+	InheritanceStrategyTABLExPERxCLASS
+)
+
+var inheritanceStrategies = map[int]string{
+	int(InheritanceStrategyNONE):            "none",
+	int(InheritanceStrategySINGLExTABLE):    "single table",
+	int(InheritanceStrategyTABLExPERxCLASS): "table per class",
+}
+
+func (thisStrategy InheritanceStrategy) String() string {
+	return inheritanceStrategies[int(thisStrategy)]
+}
+
+// Val helps implement the IEnum interface
+func (thisStrategy InheritanceStrategy) Val() int {
+	return int(thisStrategy)
+}
+
+// Values helps implement the IEnum interface
+func (thisStrategy InheritanceStrategy) Values() map[int]string {
+	return inheritanceStrategies
+}
@@ -0,0 +1,119 @@
+// ------------------------------------------------------------------------------------------------
+// The code here is about the hierarchies of classes inheriting from abstract classes, and how
+// they're persisted
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"slices"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+// in a single table hierarchy, the column telling the class of the BO persisted in each row
+const discriminatorCOLUMN = "class_name"
+
+func (boClass *businessObjectSpecs) SetInheritance(strategy InheritanceStrategy) {
+	boClass.inheritance = strategy
+}
+
+// links each registered class to the class it inherits from, i.e. the BO type embedded at the 1st position
+// of its struct - if it's not BusinessObject; this should be done once all the classes are registered
+func initClassHierarchies() {
+	for name, boSpecs := range specsRegistry.items {
+		if class := classRegistry.items[name]; class != nil {
+			superField := utils.TypeOf(class.NewObject(), true).Field(0)
+			if superField.IsAnonymous() {
+				boSpecs.base().superSpecs = specsForName(className(superField.Type().Name()))
+			}
+		}
+	}
+}
+
+// returns the closest ancestor of this class having an inheritance strategy, if any
+func (boClass *businessObjectSpecs) getMappedHierarchyRoot() *businessObjectSpecs {
+	for ancestor := boClass.superSpecs; ancestor != nil; ancestor = ancestor.base().superSpecs {
+		if ancestor.base().inheritance != InheritanceStrategyNONE {
+			return ancestor.base()
+		}
+	}
+
+	return nil
+}
+
+// returns true if this class is the given one, or inherits from it, directly or not
+func (boClass *businessObjectSpecs) inheritsFrom(other *businessObjectSpecs) bool {
+	for ancestor := IBusinessObjectSpecs(boClass); ancestor != nil; ancestor = ancestor.base().superSpecs {
+		if ancestor.base() == other {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns the concrete classes whose BOs are also BOs of this class, i.e. this class if it's concrete,
+// and all the concrete classes inheriting from it, directly or not; sorted by name
+func (boClass *businessObjectSpecs) getConcreteSubSpecs() (result []IBusinessObjectSpecs) {
+	for _, boSpecs := range core.GetSortedValues(specsRegistry.items) {
+		if !boSpecs.base().abstract && boSpecs.base().inheritsFrom(boClass) {
+			result = append(result, boSpecs)
+		}
+	}
+
+	return
+}
+
+// returns true if the BOs of this class are persisted in a table shared with other classes
+func (boClass *businessObjectSpecs) isInSingleTable() bool {
+	if boClass.inheritance == InheritanceStrategySINGLExTABLE {
+		return true
+	}
+
+	root := boClass.getMappedHierarchyRoot()
+
+	return root != nil && root.inheritance == InheritanceStrategySINGLExTABLE
+}
+
+// returns true if the BOs loaded for this class can be instances of the classes inheriting from it, which cannot be
+// typed as this class' struct; they have to be handled as IBusinessObject, e.g. with LoadBOs[IBusinessObject]
+func (boClass *businessObjectSpecs) loadsSubclassInstances() bool {
+	if !boClass.isInSingleTable() && boClass.getTablePerClassSpecs() == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(boClass.getConcreteSubSpecs(), func(concrete IBusinessObjectSpecs) bool {
+		return concrete.base() != boClass
+	})
+}
+
+// for an abstract class mapped with 1 table per concrete class, returns the concrete classes to read from
+func (boClass *businessObjectSpecs) getTablePerClassSpecs() []IBusinessObjectSpecs {
+	if !boClass.abstract {
+		return nil
+	}
+
+	root := core.IfThenElse(boClass.inheritance != InheritanceStrategyNONE, boClass, boClass.getMappedHierarchyRoot())
+	if root == nil || root.inheritance != InheritanceStrategyTABLExPERxCLASS {
+		return nil
+	}
+
+	return boClass.getConcreteSubSpecs()
+}
+
+// returns all the persisted properties of the given classes, without any duplicated column; the properties
+// of the 1st class come first, in the same order
+func getUnionOfPersistedProperties(classes []IBusinessObjectSpecs) (result []iBusinessObjectProperty) {
+	columns := []string{}
+	for _, boSpecs := range classes {
+		for _, property := range boSpecs.base().getPersistedProperties() {
+			if !slices.Contains(columns, property.getColumnName()) {
+				columns = append(columns, property.getColumnName())
+				result = append(result, property)
+			}
+		}
+	}
+
+	return
+}
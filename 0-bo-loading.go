@@ -3,17 +3,95 @@
 // ------------------------------------------------------------------------------------------------
 package goald
 
+import core "github.com/aldesgroup/corego"
+
 // A loading type is a key for an object that fully describe how to load business objects
 // with their relationships; a class can define several loading types, used in various situations
 type LoadingType string
 
+// A loading scenario tells which relationship should be loaded along with a BO, and, recursively,
+// which relationships should be loaded along with the BOs pointed by this relationship
 type LoadingScenario struct {
+	loadingType  LoadingType        // only valued for the root of a scenario tree
+	relationship *Relationship      // the relationship to load
+	with         []*LoadingScenario // what to load along with the BOs pointed by the relationship
 }
 
 func (thisBO *BusinessObject) Load(loadingType LoadingType, with ...*LoadingScenario) *LoadingScenario {
-	return nil
+	return &LoadingScenario{loadingType: loadingType, with: with}
 }
 
+// Allows to declare that the given relationship should be loaded, along with the given sub-scenarios
 func With(relationship *Relationship, with ...*LoadingScenario) *LoadingScenario {
-	return nil
+	return &LoadingScenario{relationship: relationship, with: with}
+}
+
+// DefineLoadingType declares, for the given class, which relationships are loaded with the given loading type
+func (boClass *businessObjectSpecs) DefineLoadingType(loadingType LoadingType, with ...*LoadingScenario) {
+	for _, scenario := range with {
+		if scenario.relationship == nil {
+			core.PanicMsg("Loading type '%s' of class '%s': only scenarios built with With() can be used here", loadingType, boClass.name)
+		}
+		if boClass.relationships[scenario.relationship.name] != scenario.relationship {
+			core.PanicMsg("Loading type '%s' of class '%s': relationship '%s' does not belong to this class",
+				loadingType, boClass.name, scenario.relationship.name)
+		}
+	}
+
+	boClass.loadingTypes[loadingType] = with
+}
+
+// returns what should be loaded along with this class' BOs, for the given loading type
+func (boClass *businessObjectSpecs) getLoadingScenarios(loadingType LoadingType) []*LoadingScenario {
+	return boClass.loadingTypes[loadingType]
+}
+
+// DefineLoadingTypeFields restricts, for the given loading type, the fields loaded from the DB and sent to the
+// clients - along with the ID - unless the request explicitly asks for other ones, with the "fields" query param
+func (boClass *businessObjectSpecs) DefineLoadingTypeFields(loadingType LoadingType, fields ...IField) {
+	for _, field := range fields {
+		if boClass.fields[field.getName()] != field {
+			core.PanicMsg("Loading type '%s' of class '%s': field '%s' does not belong to this class",
+				loadingType, boClass.name, field.getName())
+		}
+	}
+
+	boClass.loadingTypeFields[loadingType] = core.MapFn(fields, func(field IField) string { return field.getName() })
+}
+
+// the names of the properties to load & send: the requested ones if any, else the ones defined for the loading type,
+// if any, along with its loaded relationships; nil means all of them
+func getProjection(boSpecs IBusinessObjectSpecs, loadingType LoadingType, requested []string) []string {
+	if len(requested) > 0 {
+		return requested
+	}
+
+	fieldNames := boSpecs.base().loadingTypeFields[loadingType]
+	if len(fieldNames) == 0 {
+		return nil
+	}
+
+	projection := append([]string{}, fieldNames...)
+	for _, scenario := range boSpecs.base().getLoadingScenarios(loadingType) {
+		projection = append(projection, scenario.relationship.name)
+	}
+
+	return projection
+}
+
+// the scenarios of the given loading type whose relationship is part of the given projection, if any
+func getProjectedScenarios(boSpecs IBusinessObjectSpecs, loadingType LoadingType, projection []string) []*LoadingScenario {
+	scenarios := boSpecs.base().getLoadingScenarios(loadingType)
+	if len(projection) == 0 {
+		return scenarios
+	}
+
+	projectedScenarios := []*LoadingScenario{}
+	for _, scenario := range scenarios {
+		if core.InSlice(projection, scenario.relationship.name) {
+			projectedScenarios = append(projectedScenarios, scenario)
+		}
+	}
+
+	return projectedScenarios
 }
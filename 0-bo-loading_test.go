@@ -0,0 +1,46 @@
+package goald
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetProjection(t *testing.T) {
+	orderSpecs := NewBusinessObjectSpecs()
+	reference := NewStringField(orderSpecs, "Reference", false)
+	lineSpecs := NewBusinessObjectSpecs()
+	lines := NewRelationship(orderSpecs, "Lines", true, lineSpecs)
+
+	orderSpecs.DefineLoadingTypeFields("light", reference)
+	orderSpecs.DefineLoadingTypeFields("withLines", reference)
+	orderSpecs.DefineLoadingType("withLines", With(lines))
+	orderSpecs.DefineLoadingType("allWithLines", With(lines))
+
+	tests := []struct {
+		name                string
+		loadingType         LoadingType
+		requested           []string
+		expectedProjection  []string
+		expectedNbScenarios int
+	}{
+		{"no loading type", "", nil, nil, 0},
+		{"loading type fields", "light", nil, []string{"Reference"}, 0},
+		{"loading type fields & relationships", "withLines", nil, []string{"Reference", "Lines"}, 1},
+		{"loading type relationships only", "allWithLines", nil, nil, 1},
+		{"requested fields", "withLines", []string{"Reference"}, []string{"Reference"}, 0},
+		{"requested fields & relationships", "withLines", []string{"Lines"}, []string{"Lines"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projection := getProjection(orderSpecs, tt.loadingType, tt.requested)
+			if !slices.Equal(projection, tt.expectedProjection) {
+				t.Errorf("getProjection() = %v, expected %v", projection, tt.expectedProjection)
+			}
+
+			if got := getProjectedScenarios(orderSpecs, tt.loadingType, projection); len(got) != tt.expectedNbScenarios {
+				t.Errorf("getProjectedScenarios() = %d scenarios, expected %d", len(got), tt.expectedNbScenarios)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// ------------------------------------------------------------------------------------------------
+// The code here is about declaring rules involving several properties of a business object class,
+// e.g. "EndDate after StartDate", or "Reason is mandatory if Status == REJECTED"
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"fmt"
+	"strconv"
+
+	core "github.com/aldesgroup/corego"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Comparators
+// ------------------------------------------------------------------------------------------------
+
+// Comparator is used to compare the values of 2 fields
+type Comparator string
+
+const (
+	ComparatorEQ  Comparator = "=="
+	ComparatorNEQ Comparator = "!="
+	ComparatorLT  Comparator = "<"
+	ComparatorLTE Comparator = "<="
+	ComparatorGT  Comparator = ">"
+	ComparatorGTE Comparator = ">="
+)
+
+// tells if the result of a comparison - i.e. -1, 0 or 1 - is what this comparator expects
+func (comparator Comparator) accepts(comparison int) bool {
+	switch comparator {
+	case ComparatorEQ:
+		return comparison == 0
+	case ComparatorNEQ:
+		return comparison != 0
+	case ComparatorLT:
+		return comparison < 0
+	case ComparatorLTE:
+		return comparison <= 0
+	case ComparatorGT:
+		return comparison > 0
+	case ComparatorGTE:
+		return comparison >= 0
+	default:
+		core.PanicMsg("Unknown comparator: %s", comparator)
+		return false
+	}
+}
+
+// ------------------------------------------------------------------------------------------------
+// Rules
+// ------------------------------------------------------------------------------------------------
+
+type iSpecsRule interface {
+	check(class IClass, bObj IBusinessObject) *FieldError // returns the violation of the rule by the given BO, if any
+	describe() string                                     // the rule, as described in the generated client models
+}
+
+// a field must have a non-zero value whenever another field has a given value
+type mandatoryIfRule struct {
+	field      IField
+	otherField IField
+	otherValue string
+}
+
+// a field's value must compare in a given way with another field's value
+type comparisonRule struct {
+	field      IField
+	comparator Comparator
+	otherField IField
+}
+
+// a multiple relationship must point to at least N business objects
+type atLeastRule struct {
+	relationship *Relationship
+	nb           int
+}
+
+func (rule *mandatoryIfRule) describe() string {
+	return fmt.Sprintf("{ rule: '%s', field: '%s', otherField: '%s', value: '%s' }",
+		ValidationRuleMANDATORYxIF, rule.field.getName(), rule.otherField.getName(), rule.otherValue)
+}
+
+func (rule *comparisonRule) describe() string {
+	return fmt.Sprintf("{ rule: '%s', field: '%s', comparator: '%s', otherField: '%s' }",
+		ValidationRuleCOMPARISON, rule.field.getName(), rule.comparator, rule.otherField.getName())
+}
+
+func (rule *atLeastRule) describe() string {
+	return fmt.Sprintf("{ rule: '%s', field: '%s', limit: %d }", ValidationRuleATxLEAST, rule.relationship.getName(), rule.nb)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Declaring rules on the specs
+// ------------------------------------------------------------------------------------------------
+
+// the given field must be valued whenever the other field has the given value - which can be a string, an enum, a number...
+func (boClass *businessObjectSpecs) MandatoryIf(field IField, otherField IField, otherValue any) {
+	boClass.rules = append(boClass.rules, &mandatoryIfRule{field: field, otherField: otherField, otherValue: ruleValueToString(otherValue)})
+}
+
+// the given field's value must compare with the other field's value as specified, e.g. EndDate > StartDate
+func (boClass *businessObjectSpecs) Compare(field IField, comparator Comparator, otherField IField) {
+	comparator.accepts(0) // making sure the comparator is a known one
+	boClass.rules = append(boClass.rules, &comparisonRule{field: field, comparator: comparator, otherField: otherField})
+}
+
+// the given multiple relationship must point to at least nb business objects
+func (boClass *businessObjectSpecs) AtLeast(relationship *Relationship, nb int) {
+	if !relationship.isMultiple() {
+		core.PanicMsg("Cannot set a minimum number of items on single relationship '%s.%s'", boClass.name, relationship.getName())
+	}
+
+	boClass.rules = append(boClass.rules, &atLeastRule{relationship: relationship, nb: nb})
+}
+
+// the value used in a rule, as it would be returned by a value mapper
+func ruleValueToString(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case IEnum:
+		return strconv.Itoa(value.Val())
+	case bool:
+		return core.BoolToString(value)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
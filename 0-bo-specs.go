@@ -5,13 +5,15 @@
 package goald
 
 import (
+	"regexp"
+	"strings"
 	"sync"
 
 	core "github.com/aldesgroup/corego"
 	"github.com/aldesgroup/goald/features/utils"
+	"github.com/google/uuid"
 )
 
-// TODO endpoints should be plural
 // TODO pagination all the way
 // TODO generate stuff for enums ?
 
@@ -21,17 +23,36 @@ import (
 type IBusinessObjectSpecs interface {
 	/* public generic methods */
 
-	SetNotPersisted() // to indicate this class has no instance persisted in a database
-	SetInDB(db *DB)   // to associate the class with the DB where its instances are stored
-	SetAbstract()     // to indicate this class does not model concrete business objects, but most probably a super class
+	SetNotPersisted()                            // to indicate this class has no instance persisted in a database
+	SetInDB(db *DB)                              // to associate the class with the DB where its instances are stored
+	SetAbstract()                                // to indicate this class does not model concrete business objects, but most probably a super class
+	SetIDStrategy(strategy IDStrategy)           // to choose how the IDs of this class' BOs are generated, if not as the app's default
+	SetTableName(tableName string)               // to map this class onto a table not named after it, e.g. in a legacy schema
+	SetReadOnly(viewOrTableName string)          // to map this class onto a view, or a table owned by another app: no migration, no writing
+	SetResourcePath(resourcePath string)         // to serve this class' BOs under a path not derived from the class name, e.g. "people" for Person
+	SetVersionField(field IField)                // to keep in this integer field the version of this class' BOs, incremented on each write
+	SetLastModifiedField(field *DateField)       // to keep in this date field the last time this class' BOs were written
+	SetInheritance(strategy InheritanceStrategy) // for an abstract class: to choose how the classes inheriting from it are persisted
+
+	// declaring which relationships are loaded along with this class' BOs, for a given loading type
+	DefineLoadingType(loadingType LoadingType, with ...*LoadingScenario)
+	DefineLoadingTypeFields(loadingType LoadingType, fields ...IField) // and which fields, if not all of them
 
 	// access to generic properties (fields & relationships)
 	ID() IField
 
+	// declaring rules involving several properties
+	MandatoryIf(field IField, otherField IField, otherValue any)    // field must be valued if otherField == otherValue
+	Compare(field IField, comparator Comparator, otherField IField) // e.g. EndDate > StartDate
+	AtLeast(relationship *Relationship, nb int)                     // the multiple relationship must point to at least nb BOs
+
 	// private methods
 	isNotPersisted() bool
+	isReadOnly() bool
 	getInDB() *DB
 	getTableName() string
+	getIDStrategy() IDStrategy
+	getLoadingScenarios(loadingType LoadingType) []*LoadingScenario
 
 	// access to the base implementation
 	base() *businessObjectSpecs
@@ -41,24 +62,36 @@ type IBusinessObjectSpecs interface {
 type className string
 
 type businessObjectSpecs struct {
-	name                    className                 // the corresponding class name
-	fields                  map[string]IField         // the objet's simple properties
-	relationships           map[string]*Relationship  // the relationships to other classes
-	inDB                    *DB                       // the associated DB, if any
-	inNoDB                  bool                      // if true, then no associated DB
-	abstract                bool                      // if true, then is class is mainly used as a super class for others
-	tableName               string                    // if persisted, the name of the corresponding DB table - should be the same as the class name most of the time
-	persistedProperties     []iBusinessObjectProperty // all the properties - fields or relationships - persisted on this class
-	relationshipsWithColumn []*Relationship           // all the relationships for which this class has a column in its table
-	idField                 IField                    // accessor to the ID field
-	usedInNativeApp         bool                      // true if this class is used in the native app
-	usedInWebApp            bool                      // true if this class is used in the web app
+	name                    className                          // the corresponding class name
+	fields                  map[string]IField                  // the objet's simple properties
+	relationships           map[string]*Relationship           // the relationships to other classes
+	inDB                    *DB                                // the associated DB, if any
+	inNoDB                  bool                               // if true, then no associated DB
+	abstract                bool                               // if true, then is class is mainly used as a super class for others
+	tableName               string                             // if persisted, the name of the corresponding DB table - should be the same as the class name most of the time
+	persistedProperties     []iBusinessObjectProperty          // all the properties - fields or relationships - persisted on this class
+	relationshipsWithColumn []*Relationship                    // all the relationships for which this class has a column in its table
+	idField                 IField                             // accessor to the ID field
+	usedInNativeApp         bool                               // true if this class is used in the native app
+	usedInWebApp            bool                               // true if this class is used in the web app
+	rules                   []iSpecsRule                       // the rules involving several properties of this class
+	idStrategy              IDStrategy                         // how the IDs are generated for this class
+	readOnly                bool                               // if true, then this class' BOs are read from a view or a table we do not manage
+	inheritance             InheritanceStrategy                // for an abstract class, how the classes inheriting from it are persisted
+	superSpecs              IBusinessObjectSpecs               // the specs of the class this class inherits from, if any - but BusinessObject
+	loadingTypes            map[LoadingType][]*LoadingScenario // what's loaded along with this class' BOs, for each loading type
+	loadingTypeFields       map[LoadingType][]string           // the names of the fields loaded & sent for a loading type, if not all of them
+	resourcePath            string                             // if set, the path of this class' endpoints, instead of the one derived from the class name
+	versionField            IField                             // if set, the field holding the version of this class' BOs, used as their ETag
+	lastModifiedField       *DateField                         // if set, the field holding the last time this class' BOs were written
 }
 
 func NewBusinessObjectSpecs() IBusinessObjectSpecs {
 	specs := &businessObjectSpecs{
-		fields:        map[string]IField{},
-		relationships: map[string]*Relationship{},
+		fields:            map[string]IField{},
+		relationships:     map[string]*Relationship{},
+		loadingTypes:      map[LoadingType][]*LoadingScenario{},
+		loadingTypeFields: map[LoadingType][]string{},
 	}
 
 	// adding the generic fields
@@ -81,6 +114,92 @@ func (boClass *businessObjectSpecs) SetAbstract() {
 	boClass.abstract = true
 }
 
+func (boClass *businessObjectSpecs) SetIDStrategy(strategy IDStrategy) {
+	boClass.idStrategy = strategy
+}
+
+func (boClass *businessObjectSpecs) SetTableName(tableName string) {
+	boClass.tableName = tableName
+}
+
+func (boClass *businessObjectSpecs) SetResourcePath(resourcePath string) {
+	boClass.resourcePath = strings.Trim(resourcePath, "/")
+}
+
+// the version is incremented by the generic write operations, and used as the BOs' ETag, instead of a hash of their values
+func (boClass *businessObjectSpecs) SetVersionField(field IField) {
+	if boClass.fields[field.getName()] != field ||
+		(field.getTypeFamily() != utils.TypeFamilyINT && field.getTypeFamily() != utils.TypeFamilyBIGINT) {
+		core.PanicMsg("Field '%s' is not an integer field of class '%s'", field.getName(), boClass.name)
+	}
+
+	boClass.versionField = field
+}
+
+// the date is set by the generic write operations, and sent to the clients through the "Last-Modified" header
+func (boClass *businessObjectSpecs) SetLastModifiedField(field *DateField) {
+	if boClass.fields[field.getName()] != field {
+		core.PanicMsg("Field '%s' does not belong to class '%s'", field.getName(), boClass.name)
+	}
+
+	boClass.lastModifiedField = field
+}
+
+// the given name, if any, overrides the table name derived from the class name
+func (boClass *businessObjectSpecs) SetReadOnly(viewOrTableName string) {
+	boClass.readOnly = true
+	if viewOrTableName != "" {
+		boClass.tableName = viewOrTableName
+	}
+}
+
+func (boClass *businessObjectSpecs) isReadOnly() bool {
+	return boClass.readOnly
+}
+
+func (boClass *businessObjectSpecs) getIDStrategy() IDStrategy {
+	if boClass.idStrategy == IDStrategyDEFAULT {
+		return defaultIDStrategy
+	}
+
+	return boClass.idStrategy
+}
+
+// the ID strategy used by the classes that do not specify one
+var defaultIDStrategy = IDStrategyIDENTITY
+
+// SetDefaultIDStrategy allows to choose how the IDs are generated for all the app's classes, but the ones
+// that specify their own strategy; this should be called before the server is started
+func SetDefaultIDStrategy(strategy IDStrategy) {
+	if strategy == IDStrategyDEFAULT {
+		core.PanicMsg("The default ID strategy must be a concrete one")
+	}
+
+	defaultIDStrategy = strategy
+}
+
+// by default, a relationship's column is named after the relationship, e.g. "customer" - which existing DBs rely on
+var relationshipColumnsSUFFIXED = false
+
+// SetRelationshipColumnsSuffixed makes the relationships' columns - the ones not given a custom name - end with "_id",
+// e.g. "customer_id"; for an existing DB, the columns must be renamed accordingly, since the automigration would
+// otherwise create new, empty ones; this should be called before the server is started
+func SetRelationshipColumnsSuffixed() {
+	relationshipColumnsSUFFIXED = true
+}
+
+// generates a new ID, if this is up to us and not the DB
+func (boClass *businessObjectSpecs) newID() BObjID {
+	switch boClass.getIDStrategy() {
+	case IDStrategyUUIDxV4:
+		return BObjID(uuid.NewString())
+	case IDStrategyUUIDxV7:
+		return BObjID(uuid.Must(uuid.NewV7()).String())
+	default:
+		return ""
+	}
+}
+
 func (boClass *businessObjectSpecs) getInDB() *DB {
 	return boClass.inDB
 }
@@ -98,6 +217,11 @@ func (boClass *businessObjectSpecs) ID() IField {
 }
 
 func (boClass *businessObjectSpecs) getTableName() string {
+	// the classes of a single table hierarchy all share the same table
+	if root := boClass.getMappedHierarchyRoot(); root != nil && root.inheritance == InheritanceStrategySINGLExTABLE {
+		return root.getTableName()
+	}
+
 	if boClass.tableName == "" {
 		boClass.tableName = core.PascalToSnake(string(boClass.name))
 	}
@@ -127,6 +251,7 @@ type iBusinessObjectProperty interface {
 	getColumnName() string
 	isMandatory() bool
 	isNotPersisted() bool
+	isUnique() bool
 }
 
 type businessObjectProperty struct {
@@ -137,6 +262,7 @@ type businessObjectProperty struct {
 	columnName   string               // if this property - field or relationship - is persisted on the owner's table
 	mandatory    bool                 // if true, then this property's value must be non-zero
 	notPersisted bool                 // if true, then this property does not have a corresponding column in the BO's table
+	unique       bool                 // if true, then 2 BOs of the owner class cannot have the same value for this property
 }
 
 func (prop *businessObjectProperty) ownerSpecs() IBusinessObjectSpecs {
@@ -158,7 +284,7 @@ func (prop *businessObjectProperty) getTypeFamily() utils.TypeFamily {
 func (prop *businessObjectProperty) getColumnName() string {
 	if prop.columnName == "" {
 		prop.columnName = core.PascalToSnake(prop.name)
-		if prop.typeFamily == utils.TypeFamilyRELATIONSHIPxMONOM {
+		if prop.typeFamily.IsRelationship() && relationshipColumnsSUFFIXED {
 			prop.columnName += "_id"
 		}
 	}
@@ -178,6 +304,10 @@ func (prop *businessObjectProperty) isNotPersisted() bool {
 	return prop.notPersisted
 }
 
+func (prop *businessObjectProperty) isUnique() bool {
+	return prop.unique
+}
+
 // ------------------------------------------------------------------------------------------------
 // Fields (simple properties) of business object classes
 // ------------------------------------------------------------------------------------------------
@@ -186,6 +316,11 @@ type IField interface {
 	iBusinessObjectProperty
 	isBuiltIn() bool
 	getDefaultValue() string
+	isZeroValue(valueAsString string) bool         // returns true if the given value is the zero value for this field's type
+	checkValue(valueAsString string) []*FieldError // returns the violations of the constraints set on this field, if any
+	isStoredInChildTable() bool                    // true for a multiple field whose values are stored in a child table
+	getChildTableName() string                     // the name of the table containing this multiple field's values, if any
+	isPatchable() bool                             // true if this field's value can be changed through a PATCH request
 	// SetDefaultValue(string) IField
 }
 
@@ -199,6 +334,8 @@ type iNumericField interface {
 type field struct {
 	businessObjectProperty
 	defaultStringValue string
+	inChildTable       bool // for a multiple field: if true, the values are stored in a child table, rather than serialized in a column
+	patchable          bool // if true, this field's value can be changed through a PATCH request
 }
 
 type numericField struct {
@@ -228,6 +365,47 @@ func (f *field) SetNotPersisted() *field {
 	return f
 }
 
+func (f *field) SetUnique() *field {
+	f.unique = true
+	return f
+}
+
+// to map this field onto a column not named after it, e.g. in a legacy schema
+func (f *field) SetColumnName(columnName string) *field {
+	f.columnName = columnName
+	return f
+}
+
+// for a multiple field, e.g. a []string: storing the values in a child table, with 1 row per value,
+// rather than in 1 column of the owner's table, as a JSON array
+func (f *field) SetStoredInChildTable() *field {
+	if !f.multiple {
+		core.PanicMsg("Field '%s' is not multiple, so its value cannot be stored in a child table", f.name)
+	}
+
+	f.inChildTable = true
+	return f
+}
+
+func (f *field) isStoredInChildTable() bool {
+	return f.inChildTable
+}
+
+func (f *field) getChildTableName() string {
+	return f.owner.getTableName() + "_" + f.getColumnName()
+}
+
+// allowing this field's value to be changed through PATCH requests; the fields not declared
+// patchable are rejected when found in a patch
+func (f *field) SetPatchable() *field {
+	f.patchable = true
+	return f
+}
+
+func (f *field) isPatchable() bool {
+	return f.patchable
+}
+
 func (f *field) isBuiltIn() bool {
 	return false
 }
@@ -255,8 +433,19 @@ type BoolField struct {
 
 type StringField struct {
 	field
-	size    int
-	atLeast int
+	size       int
+	atLeast    int
+	pattern    *regexp.Regexp     // if set, the value must match this regular expression
+	email      bool               // if true, the value must be an email address
+	url        bool               // if true, the value must be an absolute URL
+	oneOf      []string           // if not empty, the value must be one of these
+	validators []*customValidator // some validators specific to the app
+}
+
+// a named function to apply on a string field's value; it should return true if the value is valid
+type customValidator struct {
+	name string
+	fn   func(valueAsString string) bool
 }
 
 func (sf *StringField) SetSize(size int, atLeast ...int) *field {
@@ -267,6 +456,37 @@ func (sf *StringField) SetSize(size int, atLeast ...int) *field {
 	return &sf.field
 }
 
+// the value must match the given regular expression, which should be compatible with both Go & JS
+func (sf *StringField) Pattern(regex string) *StringField {
+	sf.pattern = regexp.MustCompile(regex)
+	return sf
+}
+
+// the value must be an email address
+func (sf *StringField) Email() *StringField {
+	sf.email = true
+	return sf
+}
+
+// the value must be an absolute URL
+func (sf *StringField) URL() *StringField {
+	sf.url = true
+	return sf
+}
+
+// the value must be one of the given ones
+func (sf *StringField) OneOf(values ...string) *StringField {
+	sf.oneOf = values
+	return sf
+}
+
+// the value must pass the given function; the same validator - i.e. with the same name - must be
+// provided to the web & native apps
+func (sf *StringField) Custom(name string, fn func(valueAsString string) bool) *StringField {
+	sf.validators = append(sf.validators, &customValidator{name: name, fn: fn})
+	return sf
+}
+
 type IntField struct {
 	numericField
 	min int
@@ -299,6 +519,7 @@ func (f *BigIntField) Min(min int64) *BigIntField {
 
 func (f *BigIntField) Max(max int64) *BigIntField {
 	f.max = max
+	f.maxSet = true
 	return f
 }
 
@@ -318,6 +539,7 @@ func (f *RealField) Min(min float32) *RealField {
 
 func (f *RealField) Max(max float32) *RealField {
 	f.max = max
+	f.maxSet = true
 	return f
 }
 
@@ -337,6 +559,7 @@ func (f *DoubleField) Min(min float64) *DoubleField {
 
 func (f *DoubleField) Max(max float64) *DoubleField {
 	f.max = max
+	f.maxSet = true
 	return f
 }
 
@@ -346,10 +569,87 @@ func (f *DoubleField) Max(max float64) *DoubleField {
 // 	return rf
 // }
 
+// an exact decimal number, e.g. for amounts of money
+type DecimalField struct {
+	field
+	precision int // the total number of significant digits, e.g. 6 in 9876.06
+	scale     int // number of digits after the decimal point, e.g. 2 in 9876.06
+}
+
+// the default format, fit for most amounts of money
+const (
+	decimalDEFAULTxPRECISION = 18
+	decimalDEFAULTxSCALE     = 2
+)
+
+func (f *DecimalField) SetPrecision(precision, scale int) *DecimalField {
+	if precision < 1 || scale < 0 || scale > precision {
+		core.PanicMsg("Invalid precision (%d) & scale (%d) for decimal field '%s'", precision, scale, f.name)
+	}
+
+	f.precision = precision
+	f.scale = scale
+	return f
+}
+
 type DateField struct {
 	field
 }
 
+// a calendar date, with no time nor timezone, e.g. a birthday
+type DateOnlyField struct {
+	field
+}
+
+// a time within any day, with no date nor timezone, e.g. an opening hour
+type TimeOfDayField struct {
+	field
+}
+
+// a time.Duration, persisted as a number of nanoseconds
+type DurationField struct {
+	field
+}
+
+// a semi-structured document - a map or a struct - stored as JSON
+type JSONField struct {
+	field
+}
+
+// raw binary data, i.e. a []byte
+type BinaryField struct {
+	field
+	maxSize int // the max number of bytes; 0 means no limit
+}
+
+func (f *BinaryField) SetMaxSize(maxSize int) *BinaryField {
+	f.maxSize = maxSize
+	return f
+}
+
+// a file attached to the BO: only its metadata are persisted with the BO, while its content is handled by a file storage
+type AttachmentField struct {
+	field
+	maxSize      int64        // the max number of bytes of the file; 0 means no limit
+	contentTypes []string     // the accepted content types, e.g. "image/png"; all are accepted if empty
+	storage      IFileStorage // where the file content is stored; if nil, the default storage is used
+}
+
+func (f *AttachmentField) SetMaxSize(maxSize int64) *AttachmentField {
+	f.maxSize = maxSize
+	return f
+}
+
+func (f *AttachmentField) SetContentTypes(contentTypes ...string) *AttachmentField {
+	f.contentTypes = contentTypes
+	return f
+}
+
+func (f *AttachmentField) SetStorage(storage IFileStorage) *AttachmentField {
+	f.storage = storage
+	return f
+}
+
 type EnumField struct {
 	field
 	enumName   string
@@ -396,12 +696,56 @@ func NewDoubleField(owner IBusinessObjectSpecs, name string, multiple bool) *Dou
 	}}).(*DoubleField)
 }
 
+func NewDecimalField(owner IBusinessObjectSpecs, name string, multiple bool) *DecimalField {
+	return owner.addField(&DecimalField{
+		field:     newField(owner, name, multiple, utils.TypeFamilyDECIMAL),
+		precision: decimalDEFAULTxPRECISION,
+		scale:     decimalDEFAULTxSCALE,
+	}).(*DecimalField)
+}
+
 func NewDateField(owner IBusinessObjectSpecs, name string, multiple bool) *DateField {
 	return owner.addField(&DateField{
 		field: newField(owner, name, multiple, utils.TypeFamilyDATE),
 	}).(*DateField)
 }
 
+func NewDateOnlyField(owner IBusinessObjectSpecs, name string, multiple bool) *DateOnlyField {
+	return owner.addField(&DateOnlyField{
+		field: newField(owner, name, multiple, utils.TypeFamilyDATExONLY),
+	}).(*DateOnlyField)
+}
+
+func NewTimeOfDayField(owner IBusinessObjectSpecs, name string, multiple bool) *TimeOfDayField {
+	return owner.addField(&TimeOfDayField{
+		field: newField(owner, name, multiple, utils.TypeFamilyTIMExOFxDAY),
+	}).(*TimeOfDayField)
+}
+
+func NewDurationField(owner IBusinessObjectSpecs, name string, multiple bool) *DurationField {
+	return owner.addField(&DurationField{
+		field: newField(owner, name, multiple, utils.TypeFamilyDURATION),
+	}).(*DurationField)
+}
+
+func NewJSONField(owner IBusinessObjectSpecs, name string, multiple bool) *JSONField {
+	return owner.addField(&JSONField{
+		field: newField(owner, name, multiple, utils.TypeFamilyJSON),
+	}).(*JSONField)
+}
+
+func NewBinaryField(owner IBusinessObjectSpecs, name string, multiple bool) *BinaryField {
+	return owner.addField(&BinaryField{
+		field: newField(owner, name, multiple, utils.TypeFamilyBINARY),
+	}).(*BinaryField)
+}
+
+func NewAttachmentField(owner IBusinessObjectSpecs, name string, multiple bool) *AttachmentField {
+	return owner.addField(&AttachmentField{
+		field: newField(owner, name, multiple, utils.TypeFamilyATTACHMENT),
+	}).(*AttachmentField)
+}
+
 func NewEnumField(owner IBusinessObjectSpecs, name string, multiple bool, enumName string) *EnumField {
 	return owner.addField(&EnumField{
 		field:    newField(owner, name, multiple, utils.TypeFamilyENUM),
@@ -455,6 +799,8 @@ func NewRelationship(owner IBusinessObjectSpecs, name string, multiple bool, tar
 		polymorphic: len(targets) > 1,
 	}
 
+	relationship.typeFamily = core.IfThenElse(relationship.polymorphic, utils.TypeFamilyRELATIONSHIPxPOLYM, utils.TypeFamilyRELATIONSHIPxMONOM)
+
 	owner.base().relationships[name] = relationship
 
 	return relationship
@@ -503,6 +849,13 @@ func (r *Relationship) SetOneWay() *Relationship {
 	return r
 }
 
+// Maps the relationship onto a column not named after it, e.g. "customer_ref" rather than "customer"
+func (r *Relationship) SetColumnName(columnName string) *Relationship {
+	r.columnName = columnName
+
+	return r
+}
+
 // returns true if this relationships, should it be persisted, needs a column on its owner's table for it
 func (r *Relationship) needsColumn() bool {
 	if r.multiple {
@@ -6,6 +6,7 @@ package goald
 import (
 	"sort"
 
+	core "github.com/aldesgroup/corego"
 	"github.com/aldesgroup/goald/features/utils"
 )
 
@@ -14,6 +15,7 @@ var (
 	typeURLxQUERYxOBJECT  = utils.TypeOf((*URLQueryParams)(nil), true)
 	typeIxBUSINESSxOBJECT = utils.TypeOf((*IBusinessObject)(nil), true)
 	typeIxENUM            = utils.TypeOf((*IEnum)(nil), true)
+	typeATTACHMENTxPTR    = utils.TypeOf((*Attachment)(nil), false)
 )
 
 // // GetAllProperties returns all this class' properties
@@ -46,7 +48,7 @@ func (boSpecs *businessObjectSpecs) getPersistedProperties() []iBusinessObjectPr
 		// let's gather all the persisted properties
 		// boSpecs.persistedProperties = make([]iBusinessObjectProperty, size)
 		for _, field := range boSpecs.fields {
-			if !field.isNotPersisted() {
+			if !field.isNotPersisted() && !field.isStoredInChildTable() {
 				boSpecs.persistedProperties = append(boSpecs.persistedProperties, field)
 			}
 		}
@@ -74,6 +76,17 @@ func (boSpecs *businessObjectSpecs) getPersistedProperties() []iBusinessObjectPr
 	return boSpecs.persistedProperties
 }
 
+// getChildTableFields returns the sorted list of the persisted multiple fields whose values are stored in child tables
+func (boSpecs *businessObjectSpecs) getChildTableFields() (result []IField) {
+	for _, field := range core.GetSortedValues(boSpecs.fields) {
+		if !field.isNotPersisted() && field.isStoredInChildTable() {
+			result = append(result, field)
+		}
+	}
+
+	return
+}
+
 // getRelationshipsWithColumn returns the sorted list of the fields that are persisted
 func (boSpecs *businessObjectSpecs) getRelationshipsWithColumn() []*Relationship {
 	// initialising it, the first time we need it
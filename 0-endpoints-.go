@@ -6,7 +6,10 @@ package goald
 import (
 	"net/http"
 	"strings"
+	"time"
+	"unicode"
 
+	core "github.com/aldesgroup/corego"
 	"github.com/aldesgroup/goald/features/hstatus"
 	"github.com/aldesgroup/goald/features/utils"
 )
@@ -18,6 +21,11 @@ type iEndpoint interface {
 	getMethod() string
 	getResourceClass() className
 	getIDProp() IField
+	getParentRelationship() *Relationship
+	getParentIDParam() string
+	getIDParam() string
+	getActionPath() string
+	getSubPath() string
 	getFullPath() string
 	getLabel() string
 	getLoadingType() LoadingType
@@ -28,31 +36,57 @@ type iEndpoint interface {
 	getInputOrParamsClass() className
 	isCalledFromWebApp() bool
 	isCalledFromNativeApp() bool
+	isFileUpload() bool
+	getMaxUploadSize() int64
+	isFileDownload() bool
+	isGenericWrite() bool
+	isPatch() bool
+	getVersionRange() (int, int)
+	isDeprecated() bool
+	getSunset() time.Time
+	isConditional() bool
+	isIdempotent() bool
 	returnOne(webCtx WebContext) (any, hstatus.Code, string)
 	returnMany(webCtx WebContext) (any, hstatus.Code, string)
 	returnOneForOne(webCtx WebContext, input any) (any, hstatus.Code, string)
 	returnOneForMany(webCtx WebContext, inputs any) (any, hstatus.Code, string)
 	returnManyForOne(webCtx WebContext, input any) (any, hstatus.Code, string)
 	returnManyForMany(webCtx WebContext, inputs any) (any, hstatus.Code, string)
+	returnOneForFile(webCtx WebContext, file *AttachedFile) (any, hstatus.Code, string)
+	returnFile(webCtx WebContext) (*AttachedFile, hstatus.Code, string)
+	returnOneForPatch(webCtx WebContext, patch Patch) (any, hstatus.Code, string)
 }
 
 // an endpoint object is parametrized by the potential objects of type I,
 // and the output objects of type O, i.e. the resource type
 type endpoint[ResourceType IBusinessObject] struct {
-	method              string      // get, post, put...
-	resourceClass       className   // the class of the objects reached through this endpoint
-	basePath            string      // the endpoint's base path, which is the lower-cased resource type name
-	actionPath          string      // do we need an additional path for a non-CRUD action, like "reduce" in: "GET /document/reduce/:id"
-	idProp              IField      // if a specific BO is targeted, this has to be through one of its properties
-	fullPath            string      // resulting from the parameter type, action path and id property
-	label               string      // short label to describe the endpoint
-	multipleOutput      bool        // if true, then the endpoint delivers arrays of BOs, rather than a single one
-	loadingType         LoadingType // how the returned resource(s) are loaded
-	bodyInputRequired   bool        // if true, then we expect something in the request body
-	multipleInput       bool        // if true, then we expect an array of BOs in the body, rather than a single one
-	inputOrParamsClass  className   // if bodyInputRequired = true, then this is the type of the input
-	calledFromWebApp    bool        // if true then this endpoint can be called from the webapp, so the BOs involved might be synced through codegen
-	calledFromNativeApp bool        // if true then this endpoint can be called from the native app, so the BOs involved might be synced through codegen
+	method              string        // get, post, put...
+	resourceClass       className     // the class of the objects reached through this endpoint
+	basePath            string        // the endpoint's base path, which is the resource type name, in plural kebab-case by default
+	actionPath          string        // do we need an additional path for a non-CRUD action, like "reduce" in: "GET /documents/reduce/:id"
+	idProp              IField        // if a specific BO is targeted, this has to be through one of its properties
+	subPath             string        // if set, the path of a part of the targeted BO, e.g. "document" in: "GET /invoices/:ID/document"
+	parentRelationship  *Relationship // if set, then the resources are reached under their parent, e.g. "GET /orders/:ID/lines"
+	fullPath            string        // resulting from the parameter type, action path and id property
+	label               string        // short label to describe the endpoint
+	multipleOutput      bool          // if true, then the endpoint delivers arrays of BOs, rather than a single one
+	loadingType         LoadingType   // how the returned resource(s) are loaded
+	bodyInputRequired   bool          // if true, then we expect something in the request body
+	multipleInput       bool          // if true, then we expect an array of BOs in the body, rather than a single one
+	inputOrParamsClass  className     // if bodyInputRequired = true, then this is the type of the input
+	calledFromWebApp    bool          // if true then this endpoint can be called from the webapp, so the BOs involved might be synced through codegen
+	calledFromNativeApp bool          // if true then this endpoint can be called from the native app, so the BOs involved might be synced through codegen
+	fileUpload          bool          // if true, then we expect a file in the request, sent through a multipart form
+	maxUploadSize       int64         // the max number of bytes of the uploaded file, if any; 0 means no limit
+	fileDownload        bool          // if true, then the endpoint delivers a file, rather than a JSON response
+	genericWrite        bool          // if true, then the endpoint is handled by a generic handler writing into the resource's table
+	patch               bool          // if true, then we expect a partial JSON document in the request body, to patch the targeted BO
+	minVersion          int           // the first version of the API serving this endpoint; 0 for an unversioned endpoint
+	maxVersion          int           // the last version of the API serving this endpoint; 0 for "up to the latest one"
+	deprecated          bool          // if true, then the responses warn the clients that this endpoint should not be used anymore
+	sunset              time.Time     // if set, the date after which this deprecated endpoint may not be served anymore
+	conditional         bool          // if true, then the GET responses carry an ETag, and the requests can be made conditional on it
+	idempotent          bool          // if true, then the requests retried with the same idempotency key get the response of the 1st one
 }
 
 func (ep *endpoint[ResourceType]) getMethod() string {
@@ -67,9 +101,54 @@ func (ep *endpoint[ResourceType]) getIDProp() IField {
 	return ep.idProp
 }
 
+func (ep *endpoint[ResourceType]) getParentRelationship() *Relationship {
+	return ep.parentRelationship
+}
+
+// the name of the path param giving the parent's ID, e.g. "ID" in "/orders/:ID/lines": the same as in the parent's own
+// routes, e.g. "/orders/:ID", since the router cannot have 2 different wildcards at the same position
+func (ep *endpoint[ResourceType]) getParentIDParam() string {
+	if ep.parentRelationship == nil {
+		return ""
+	}
+
+	return ep.parentRelationship.targets[0].ID().getName()
+}
+
+// the name of the path param giving the targeted BO's property, e.g. "ID"; for a nested endpoint, it's prefixed with the
+// resource's name, e.g. "orderLineID" in "/orders/:ID/lines/:orderLineID", not to be mixed up with the parent's ID
+func (ep *endpoint[ResourceType]) getIDParam() string {
+	if ep.idProp == nil {
+		return ""
+	}
+
+	if ep.parentRelationship != nil {
+		return core.PascalToCamel(string(ep.resourceClass)) + ep.idProp.getName()
+	}
+
+	return ep.idProp.getName()
+}
+
+func (ep *endpoint[ResourceType]) getActionPath() string {
+	return ep.actionPath
+}
+
+func (ep *endpoint[ResourceType]) getSubPath() string {
+	return ep.subPath
+}
+
 func (ep *endpoint[ResourceType]) getFullPath() string {
 	if ep.fullPath == "" {
-		ep.fullPath = "/" + ep.basePath
+		if ep.parentRelationship != nil {
+			// e.g. "/orders/:ID/lines", "lines" being the parent's relationship to its children
+			ep.fullPath = "/" + getResourcePath(ep.parentRelationship.targets[0].base().name) + "/:" + ep.getParentIDParam() +
+				"/" + toKebabCase(ep.parentRelationship.backRefs[0].getName())
+		} else {
+			if ep.basePath == "" {
+				ep.basePath = getResourcePath(ep.resourceClass)
+			}
+			ep.fullPath = "/" + ep.basePath
+		}
 		if ep.actionPath != "" {
 			if ep.actionPath[0:1] == "/" {
 				ep.fullPath += ep.actionPath
@@ -78,7 +157,10 @@ func (ep *endpoint[ResourceType]) getFullPath() string {
 			}
 		}
 		if ep.idProp != nil {
-			ep.fullPath += "/:" + ep.idProp.getName()
+			ep.fullPath += "/:" + ep.getIDParam()
+		}
+		if ep.subPath != "" {
+			ep.fullPath += "/" + ep.subPath
 		}
 	}
 
@@ -121,6 +203,46 @@ func (ep *endpoint[ResourceType]) isCalledFromNativeApp() bool {
 	return ep.calledFromNativeApp
 }
 
+func (ep *endpoint[ResourceType]) isFileUpload() bool {
+	return ep.fileUpload
+}
+
+func (ep *endpoint[ResourceType]) getMaxUploadSize() int64 {
+	return ep.maxUploadSize
+}
+
+func (ep *endpoint[ResourceType]) isFileDownload() bool {
+	return ep.fileDownload
+}
+
+func (ep *endpoint[ResourceType]) isGenericWrite() bool {
+	return ep.genericWrite
+}
+
+func (ep *endpoint[ResourceType]) isPatch() bool {
+	return ep.patch
+}
+
+func (ep *endpoint[ResourceType]) getVersionRange() (int, int) {
+	return ep.minVersion, ep.maxVersion
+}
+
+func (ep *endpoint[ResourceType]) isDeprecated() bool {
+	return ep.deprecated
+}
+
+func (ep *endpoint[ResourceType]) getSunset() time.Time {
+	return ep.sunset
+}
+
+func (ep *endpoint[ResourceType]) isConditional() bool {
+	return ep.conditional
+}
+
+func (ep *endpoint[ResourceType]) isIdempotent() bool {
+	return ep.idempotent
+}
+
 func (ep *endpoint[ResourceType]) returnOne(webCtx WebContext) (any, hstatus.Code, string) {
 	panic("no generic implementation here")
 }
@@ -145,6 +267,18 @@ func (ep *endpoint[ResourceType]) returnManyForMany(webCtx WebContext, inputs an
 	panic("no generic implementation here")
 }
 
+func (ep *endpoint[ResourceType]) returnOneForFile(webCtx WebContext, file *AttachedFile) (any, hstatus.Code, string) {
+	panic("no generic implementation here")
+}
+
+func (ep *endpoint[ResourceType]) returnFile(webCtx WebContext) (*AttachedFile, hstatus.Code, string) {
+	panic("no generic implementation here")
+}
+
+func (ep *endpoint[ResourceType]) returnOneForPatch(webCtx WebContext, patch Patch) (any, hstatus.Code, string) {
+	panic("no generic implementation here")
+}
+
 // ------------------------------------------------------------------------------------------------
 // Endpoint declaration & building
 // ------------------------------------------------------------------------------------------------
@@ -168,7 +302,6 @@ func newEndpoint[InputOrParamsType, ResourceType IBusinessObject](
 	return &endpoint[ResourceType]{
 		method:             method,
 		resourceClass:      resourceClsName,
-		basePath:           strings.ToLower(string(resourceClsName)),
 		multipleOutput:     multipleOutput,
 		loadingType:        loadingType,
 		bodyInputRequired:  bodyInputRequired,
@@ -191,6 +324,20 @@ func (thisEndpoint *endpoint[ResourceType]) TargetWith(idProp IField) *endpoint[
 	return thisEndpoint
 }
 
+// Nesting this endpoint under the route of the parent BOs, given the "child to parent" relationship, e.g. "/orders/:ID/lines";
+// the parent ID is then available through the web context, and the BOs read or written must belong to this parent
+func (thisEndpoint *endpoint[ResourceType]) Under(childToParent *Relationship) *endpoint[ResourceType] {
+	if childToParent.relationType != relationshipTypeCHILDxTOxPARENT || childToParent.polymorphic ||
+		specsForName(thisEndpoint.resourceClass).base().relationships[childToParent.getName()] != childToParent {
+		core.PanicMsg("Relationship '%s' is not a child-to-parent relationship of class '%s'",
+			childToParent.getName(), thisEndpoint.resourceClass)
+	}
+
+	thisEndpoint.parentRelationship = childToParent
+
+	return thisEndpoint
+}
+
 // Providing a short description for this endpoint
 func (thisEndpoint *endpoint[ResourceType]) Label(label string) *endpoint[ResourceType] {
 	thisEndpoint.label = label
@@ -198,6 +345,54 @@ func (thisEndpoint *endpoint[ResourceType]) Label(label string) *endpoint[Resour
 	return thisEndpoint
 }
 
+// Serving this endpoint in 1 version of the API only, i.e. under the "/v<version>" prefix; the unversioned
+// endpoints are served in all the versions of the API
+func (thisEndpoint *endpoint[ResourceType]) Version(version int) *endpoint[ResourceType] {
+	return thisEndpoint.Versions(version, version)
+}
+
+// Serving this endpoint from the "from" version of the API to the "to" version, or up to the latest one if to = 0
+func (thisEndpoint *endpoint[ResourceType]) Versions(from int, to int) *endpoint[ResourceType] {
+	if from < 1 || (to != 0 && to < from) {
+		core.PanicMsg("Invalid version range [%d, %d] for endpoint %s on '%s'", from, to, thisEndpoint.method, thisEndpoint.resourceClass)
+	}
+
+	thisEndpoint.minVersion = from
+	thisEndpoint.maxVersion = to
+
+	return thisEndpoint
+}
+
+// Flagging this endpoint as deprecated, with the date after which it may not be served anymore, if known
+func (thisEndpoint *endpoint[ResourceType]) Deprecated(sunset time.Time) *endpoint[ResourceType] {
+	thisEndpoint.deprecated = true
+	thisEndpoint.sunset = sunset
+
+	return thisEndpoint
+}
+
+// Making the requests on this endpoint conditional: the GET responses carry the "ETag" - and "Last-Modified" - headers,
+// so that a client sending them back through "If-None-Match" - or "If-Modified-Since" - gets a 304 when nothing has
+// changed, and the PUT / PATCH / DELETE requests can send an "If-Match" header, to prevent lost updates
+func (thisEndpoint *endpoint[ResourceType]) Conditional() *endpoint[ResourceType] {
+	thisEndpoint.conditional = true
+
+	return thisEndpoint
+}
+
+// Making this POST endpoint idempotent: a request sent with an "Idempotency-Key" header is handled only once, and
+// retrying it with the same key - within the configured TTL - replays the response kept from the 1st time
+func (thisEndpoint *endpoint[ResourceType]) Idempotent() *endpoint[ResourceType] {
+	if thisEndpoint.method != http.MethodPost || thisEndpoint.fileUpload {
+		core.PanicMsg("Only the POST endpoints with no file upload can be idempotent, not %s on '%s'",
+			thisEndpoint.method, thisEndpoint.resourceClass)
+	}
+
+	thisEndpoint.idempotent = true
+
+	return thisEndpoint
+}
+
 // Indicating that this endpoint can be called from the associated web app (through Aldev),
 // so that I/O code can be automatically generated within it
 func (thisEndpoint *endpoint[ResourceType]) SetCalledFromWebApp() *endpoint[ResourceType] {
@@ -226,6 +421,62 @@ func (thisEndpoint *endpoint[ResourceType]) SetCalledFromNativeApp() *endpoint[R
 	return thisEndpoint
 }
 
+// ------------------------------------------------------------------------------------------------
+// Resource paths
+// ------------------------------------------------------------------------------------------------
+
+// how the resource paths are derived from the class names
+var resourcePathNaming = toPluralKebabCase
+
+// SetResourcePathNaming allows to change how the resource paths are derived from the class names, e.g. to keep them
+// singular; the classes setting their own path with SetResourcePath are not impacted. This should be called before
+// the server is started
+func SetResourcePathNaming(naming func(clsName string) string) {
+	resourcePathNaming = naming
+}
+
+// the path of the resources of the given class, e.g. "order-lines"
+func getResourcePath(clsName className) string {
+	if resourcePath := specsForName(clsName).base().resourcePath; resourcePath != "" {
+		return resourcePath
+	}
+
+	return resourcePathNaming(string(clsName))
+}
+
+// e.g. "OrderLine" => "order-lines", "Company" => "companies", "Address" => "addresses"
+func toPluralKebabCase(name string) string {
+	singular := toKebabCase(name)
+
+	switch {
+	case strings.HasSuffix(singular, "y") && len(singular) > 1 && !strings.ContainsAny(singular[len(singular)-2:len(singular)-1], "aeiou"):
+		return singular[:len(singular)-1] + "ies"
+	case strings.HasSuffix(singular, "s") || strings.HasSuffix(singular, "x") || strings.HasSuffix(singular, "z") ||
+		strings.HasSuffix(singular, "ch") || strings.HasSuffix(singular, "sh"):
+		return singular + "es"
+	default:
+		return singular + "s"
+	}
+}
+
+// e.g. "OrderLine" => "order-line", "URLQueryParams" => "url-query-params"
+func toKebabCase(name string) string {
+	runes := []rune(name)
+	result := []rune{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// a new word starts here, after a lower case letter or a digit, or at the end of an acronym
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				result = append(result, '-')
+			}
+			r = unicode.ToLower(r)
+		}
+		result = append(result, r)
+	}
+
+	return string(result)
+}
+
 // ------------------------------------------------------------------------------------------------
 // Public functions
 // ------------------------------------------------------------------------------------------------
@@ -274,6 +525,50 @@ func PutOne[InputType, ResourceType IBusinessObject](
 	return handleOneForOne[ResourceType, ResourceType](http.MethodPut, handlerFunc, loadingType)
 }
 
+// Declaring an endpoint to return 1 BO instance from a PATCH request, whose body is a partial JSON document
+func PatchOne[ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext, patch Patch) (ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *oneForPatchEndpoint[ResourceType] {
+
+	return handleOneForPatch[ResourceType](http.MethodPatch, handlerFunc, loadingType)
+}
+
+// Declaring an endpoint to return N BO instances from 1 POSTed BO instance
+func PostOneGetMany[InputType, ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext, input InputType) ([]ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *manyForOneEndpoint[InputType, ResourceType] {
+
+	return handleManyForOne[InputType, ResourceType](http.MethodPost, handlerFunc, loadingType, true)
+}
+
+// Declaring an endpoint to return 1 BO instance from N POSTed BO instances
+func PostManyGetOne[InputType, ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext, input []InputType) (ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *oneForManyEndpoint[InputType, ResourceType] {
+
+	return handleOneForMany[InputType, ResourceType](http.MethodPost, handlerFunc, loadingType)
+}
+
+// Declaring an endpoint to return N BO instances from N PUT BO instances
+func PutMany[ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext, input []ResourceType) ([]ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *manyForManyEndpoint[ResourceType, ResourceType] {
+
+	return handleManyForMany[ResourceType, ResourceType](http.MethodPut, handlerFunc, loadingType)
+}
+
+// Declaring an endpoint to delete N BO instances, given in the body of a DELETE request
+func DeleteMany[ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext, input []ResourceType) ([]ResourceType, hstatus.Code, string),
+) *manyForManyEndpoint[ResourceType, ResourceType] {
+
+	return handleManyForMany[ResourceType, ResourceType](http.MethodDelete, handlerFunc, "")
+}
+
 // Declaring an endpoint to return N BO instance from N POSTed BO instances
 func PostManyGetMany[InputType, ResourceType IBusinessObject](
 	handlerFunc func(webCtx WebContext, input []InputType) ([]ResourceType, hstatus.Code, string),
@@ -291,3 +586,20 @@ func GetManyWithParams[ResourceType IBusinessObject, QueryParamsType IURLQueryPa
 
 	return handleManyForOne[QueryParamsType, ResourceType](http.MethodGet, handlerFunc, loadingType, false)
 }
+
+// Declaring an endpoint to return 1 BO instance from 1 file, POSTed through a multipart form
+func UploadFile[ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext, file *AttachedFile) (ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *oneForFileEndpoint[ResourceType] {
+
+	return handleOneForFile[ResourceType](http.MethodPost, handlerFunc, loadingType)
+}
+
+// Declaring an endpoint to return 1 file - rather than JSON - from a GET request
+func DownloadFile[ResourceType IBusinessObject](
+	handlerFunc func(webCtx WebContext) (*AttachedFile, hstatus.Code, string),
+) *fileForNoneEndpoint[ResourceType] {
+
+	return handleFile[ResourceType](http.MethodGet, handlerFunc)
+}
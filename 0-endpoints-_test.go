@@ -0,0 +1,91 @@
+package goald
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aldesgroup/goald/features/hstatus"
+)
+
+type routeTestOrder struct {
+	BusinessObject
+}
+
+type routeTestOrderLine struct {
+	BusinessObject
+}
+
+// an "Order" class, with its "Lines", served under "/orders"
+func registerRouteTestClasses(t *testing.T) (orderSpecs, lineSpecs IBusinessObjectSpecs, lineToOrder *Relationship) {
+	orderSpecs = NewBusinessObjectSpecs()
+	orderSpecs.SetNotPersisted()
+	orderSpecs.SetResourcePath("orders")
+	lineSpecs = NewBusinessObjectSpecs()
+	lineSpecs.SetNotPersisted()
+	lineSpecs.SetResourcePath("order-lines")
+
+	orderToLines := NewRelationship(orderSpecs, "Lines", true, lineSpecs)
+	lineToOrder = NewRelationship(lineSpecs, "Order", false, orderSpecs).SetChildToParent(orderToLines)
+
+	registerTestClass(t, "routeTestOrder", orderSpecs, nil)
+	registerTestClass(t, "routeTestOrderLine", lineSpecs, nil)
+
+	return
+}
+
+func TestNestedRoutesNextToParentRoutes(t *testing.T) {
+	useTestEndpoints(t)
+	orderSpecs, lineSpecs, lineToOrder := registerRouteTestClasses(t)
+
+	GenericHandleRead[*routeTestOrder](orderSpecs.ID(), "")
+	GenericHandleDelete[*routeTestOrder](orderSpecs.ID())
+	GetMany[*routeTestOrderLine](func(webCtx WebContext) ([]*routeTestOrderLine, hstatus.Code, string) {
+		return nil, hstatus.OK, ""
+	}, "").Under(lineToOrder)
+	GetOne[*routeTestOrderLine](func(webCtx WebContext) (*routeTestOrderLine, hstatus.Code, string) {
+		return nil, hstatus.OK, ""
+	}, "").Under(lineToOrder).TargetWith(lineSpecs.ID())
+
+	router := mountTestEndpoints(t)
+
+	checkTestRoute(t, router, http.MethodGet, "/api/orders/12", map[string]string{"ID": "12"})
+	checkTestRoute(t, router, http.MethodDelete, "/api/orders/12", map[string]string{"ID": "12"})
+	checkTestRoute(t, router, http.MethodGet, "/api/orders/12/lines", map[string]string{"ID": "12"})
+	checkTestRoute(t, router, http.MethodGet, "/api/orders/12/lines/3", map[string]string{"ID": "12", "routeTestOrderLineID": "3"})
+}
+
+func TestEndpointPathParams(t *testing.T) {
+	useTestEndpoints(t)
+	orderSpecs, lineSpecs, lineToOrder := registerRouteTestClasses(t)
+
+	tests := []struct {
+		name             string
+		ep               iEndpoint
+		expectedPath     string
+		expectedParentID string
+		expectedID       string
+	}{
+		{"parent, targeted", GenericHandleRead[*routeTestOrder](orderSpecs.ID(), ""),
+			"/orders/:ID", "", "ID"},
+		{"nested",
+			GetMany[*routeTestOrderLine](nil, "").Under(lineToOrder),
+			"/orders/:ID/lines", "ID", ""},
+		{"nested, targeted",
+			GetOne[*routeTestOrderLine](nil, "").Under(lineToOrder).TargetWith(lineSpecs.ID()),
+			"/orders/:ID/lines/:routeTestOrderLineID", "ID", "routeTestOrderLineID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ep.getFullPath(); got != tt.expectedPath {
+				t.Errorf("getFullPath() = %s, expected %s", got, tt.expectedPath)
+			}
+			if got := tt.ep.getParentIDParam(); got != tt.expectedParentID {
+				t.Errorf("getParentIDParam() = %s, expected %s", got, tt.expectedParentID)
+			}
+			if got := tt.ep.getIDParam(); got != tt.expectedID {
+				t.Errorf("getIDParam() = %s, expected %s", got, tt.expectedID)
+			}
+		})
+	}
+}
@@ -7,9 +7,17 @@ import (
 	"github.com/aldesgroup/goald/features/hstatus"
 )
 
-// Simply listing the resources of a targeted type
+// Simply listing the resources of a targeted type - only the ones belonging to the parent, for a nested endpoint
 func HandleGetAll[ResourceType IBusinessObject](webCtx WebContext) ([]ResourceType, hstatus.Code, string) {
-	list, errList := LoadBOs[ResourceType](webCtx.GetBloContext(), webCtx.GetResource(), webCtx.GetResourceLoadingType())
+	var list []ResourceType
+	var errList error
+	if childToParent := webCtx.getParentRelationship(); childToParent != nil {
+		list, errList = LoadChildBOs[ResourceType](webCtx.GetBloContext(), childToParent, webCtx.GetParentID(),
+			webCtx.GetResourceLoadingType(), webCtx.GetSelectedFields()...)
+	} else {
+		list, errList = LoadBOs[ResourceType](webCtx.GetBloContext(), webCtx.GetResource(), webCtx.GetResourceLoadingType(),
+			webCtx.GetSelectedFields()...)
+	}
 	if errList != nil {
 		msg := ErrorC(errList, "Could not get a list of '%s' instances", webCtx.GetResource().base().name).Error()
 		return nil, hstatus.InternalServerError, msg
@@ -99,34 +99,48 @@ func handleOneForOne[InputType, ResourceType IBusinessObject](
 
 // adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
 func (ep *oneForOneEndpoint[InputType, ResourceType]) returnOneForOne(webCtx WebContext, input any) (any, hstatus.Code, string) {
+	if errParent := checkParentOfInputs(webCtx, []InputType{input.(InputType)}); errParent != nil {
+		return nil, hstatus.BadRequest, errParent.Error()
+	}
+
 	return ep.handlerFunc(webCtx, input.(InputType))
 }
 
-// // ------------------------------------------------------------------------------------------------
-// // The different endpoint types: (4) = 1 resource for N inputs
-// // ------------------------------------------------------------------------------------------------
+// ------------------------------------------------------------------------------------------------
+// The different endpoint types: (4) = 1 resource for N inputs
+// ------------------------------------------------------------------------------------------------
 
-// type oneForManyEndpoint[InputType, ResourceType IBusinessObject] struct {
-// 	*endpoint[ResourceType]
-// 	handlerFunc func(webCtx WebContext, input []InputType) (ResourceType, hstatus.Code, string)
-// }
+type oneForManyEndpoint[InputType, ResourceType IBusinessObject] struct {
+	*endpoint[ResourceType]
+	handlerFunc func(webCtx WebContext, input []InputType) (ResourceType, hstatus.Code, string)
+}
+
+func handleOneForMany[InputType, ResourceType IBusinessObject](
+	method string,
+	handlerFunc func(webCtx WebContext, input []InputType) (ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *oneForManyEndpoint[InputType, ResourceType] {
 
-// func handleOneForMany[InputType, ResourceType IBusinessObject](
-// 	method string,
-// 	handlerFunc func(webCtx WebContext, input []InputType) (ResourceType, hstatus.Code, string),
-// 	loadingType LoadingType,
-// ) *oneForManyEndpoint[InputType, ResourceType] {
+	return registerEndpoint(&oneForManyEndpoint[InputType, ResourceType]{
+		endpoint: newEndpoint[InputType, ResourceType](
+			false,
+			method,
+			loadingType,
+			true,
+			true,
+			false),
+		handlerFunc: handlerFunc,
+	}).(*oneForManyEndpoint[InputType, ResourceType])
+}
 
-// 	return registerEndpoint(&oneForManyEndpoint[InputType, ResourceType]{
-// 		endpoint:    newEndpoint[ResourceType](false, method, loadingType, true, true),
-// 		handlerFunc: handlerFunc,
-// 	}).(*oneForManyEndpoint[InputType, ResourceType])
-// }
+// adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
+func (ep *oneForManyEndpoint[InputType, ResourceType]) returnOneForMany(webCtx WebContext, inputs any) (any, hstatus.Code, string) {
+	if errParent := checkParentOfInputs(webCtx, inputs.([]InputType)); errParent != nil {
+		return nil, hstatus.BadRequest, errParent.Error()
+	}
 
-// // adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
-// func (ep *oneForManyEndpoint[InputType, ResourceType]) returnOneForMany(webCtx WebContext, inputs any) (any, hstatus.Code, string) {
-// 	return ep.handlerFunc(webCtx, inputs.([]InputType))
-// }
+	return ep.handlerFunc(webCtx, inputs.([]InputType))
+}
 
 // ------------------------------------------------------------------------------------------------
 // The different endpoint types: (5) = N resources for 1 input
@@ -158,6 +172,10 @@ func handleManyForOne[InputOrParamsType, ResourceType IBusinessObject](
 
 // adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
 func (ep *manyForOneEndpoint[InputOrParamsType, ResourceType]) returnManyForOne(webCtx WebContext, input any) (any, hstatus.Code, string) {
+	if errParent := checkParentOfInputs(webCtx, []InputOrParamsType{input.(InputOrParamsType)}); errParent != nil {
+		return nil, hstatus.BadRequest, errParent.Error()
+	}
+
 	return ep.handlerFunc(webCtx, input.(InputOrParamsType))
 }
 
@@ -190,9 +208,124 @@ func handleManyForMany[InputType, ResourceType IBusinessObject](
 
 // adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
 func (ep *manyForManyEndpoint[InputType, ResourceType]) returnManyForMany(webCtx WebContext, inputs any) (any, hstatus.Code, string) {
+	if errParent := checkParentOfInputs(webCtx, inputs.([]InputType)); errParent != nil {
+		return nil, hstatus.BadRequest, errParent.Error()
+	}
+
 	return ep.handlerFunc(webCtx, inputs.([]InputType))
 }
 
+// ------------------------------------------------------------------------------------------------
+// The different endpoint types: (7) = 1 resource for 1 uploaded file
+// ------------------------------------------------------------------------------------------------
+
+type oneForFileEndpoint[ResourceType IBusinessObject] struct {
+	*endpoint[ResourceType]
+	handlerFunc func(webCtx WebContext, file *AttachedFile) (ResourceType, hstatus.Code, string)
+}
+
+func handleOneForFile[ResourceType IBusinessObject](
+	method string,
+	handlerFunc func(webCtx WebContext, file *AttachedFile) (ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *oneForFileEndpoint[ResourceType] {
+
+	ep := newEndpoint[ResourceType, ResourceType](
+		false,
+		method,
+		loadingType,
+		false,
+		false,
+		false)
+	ep.fileUpload = true
+
+	return registerEndpoint(&oneForFileEndpoint[ResourceType]{
+		endpoint:    ep,
+		handlerFunc: handlerFunc,
+	}).(*oneForFileEndpoint[ResourceType])
+}
+
+// Limiting the size of the uploaded files, so that a too big request is rejected before being read entirely
+func (ep *oneForFileEndpoint[ResourceType]) SetMaxUploadSize(maxSize int64) *oneForFileEndpoint[ResourceType] {
+	ep.maxUploadSize = maxSize
+
+	return ep
+}
+
+// adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
+func (ep *oneForFileEndpoint[ResourceType]) returnOneForFile(webCtx WebContext, file *AttachedFile) (any, hstatus.Code, string) {
+	return ep.handlerFunc(webCtx, file)
+}
+
+// ------------------------------------------------------------------------------------------------
+// The different endpoint types: (8) = 1 file for no input
+// ------------------------------------------------------------------------------------------------
+
+type fileForNoneEndpoint[ResourceType IBusinessObject] struct {
+	*endpoint[ResourceType]
+	handlerFunc func(webCtx WebContext) (*AttachedFile, hstatus.Code, string)
+}
+
+func handleFile[ResourceType IBusinessObject](
+	method string,
+	handlerFunc func(webCtx WebContext) (*AttachedFile, hstatus.Code, string),
+) *fileForNoneEndpoint[ResourceType] {
+
+	ep := newEndpoint[ResourceType, ResourceType](
+		false,
+		method,
+		"",
+		false,
+		false,
+		false)
+	ep.fileDownload = true
+
+	return registerEndpoint(&fileForNoneEndpoint[ResourceType]{
+		endpoint:    ep,
+		handlerFunc: handlerFunc,
+	}).(*fileForNoneEndpoint[ResourceType])
+}
+
+// adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
+func (ep *fileForNoneEndpoint[ResourceType]) returnFile(webCtx WebContext) (*AttachedFile, hstatus.Code, string) {
+	return ep.handlerFunc(webCtx)
+}
+
+// ------------------------------------------------------------------------------------------------
+// The different endpoint types: (9) = 1 resource for 1 patch
+// ------------------------------------------------------------------------------------------------
+
+type oneForPatchEndpoint[ResourceType IBusinessObject] struct {
+	*endpoint[ResourceType]
+	handlerFunc func(webCtx WebContext, patch Patch) (ResourceType, hstatus.Code, string)
+}
+
+func handleOneForPatch[ResourceType IBusinessObject](
+	method string,
+	handlerFunc func(webCtx WebContext, patch Patch) (ResourceType, hstatus.Code, string),
+	loadingType LoadingType,
+) *oneForPatchEndpoint[ResourceType] {
+
+	ep := newEndpoint[ResourceType, ResourceType](
+		false,
+		method,
+		loadingType,
+		false,
+		false,
+		false)
+	ep.patch = true
+
+	return registerEndpoint(&oneForPatchEndpoint[ResourceType]{
+		endpoint:    ep,
+		handlerFunc: handlerFunc,
+	}).(*oneForPatchEndpoint[ResourceType])
+}
+
+// adapting the parametrized function to a generic format that the main *httpRequestContext.serve() can call
+func (ep *oneForPatchEndpoint[ResourceType]) returnOneForPatch(webCtx WebContext, patch Patch) (any, hstatus.Code, string) {
+	return ep.handlerFunc(webCtx, patch)
+}
+
 // ------------------------------------------------------------------------------------------------
 // Querying for BOs through URLs
 // ------------------------------------------------------------------------------------------------
@@ -18,15 +18,17 @@ import (
 
 // A Class Core is a set of information fields that are common to all the Class objects.
 type IClassCore interface {
-	getClassName() className                                // class of the associated Business Object
-	getLastBOMod() time.Time                                // last modification of the associated Business Object
-	getModule() moduleName                                  // the application or library in which the associated BO is developed
-	setModule(module moduleName)                            // setting the module
-	getSrcPath() string                                     // source path of the associated Business Object
-	isInterface() bool                                      // tells if the class is a concrete one, or an interface
-	AsInterface() IClassCore                                // sets the class as an interface
-	GetValueAsString(IBusinessObject, string) string        // returning a BO's field's value, given the field's name
-	SetValueAsString(IBusinessObject, string, string) error // setting a BO's field's value, given the field's name
+	getClassName() className                                     // class of the associated Business Object
+	getLastBOMod() time.Time                                     // last modification of the associated Business Object
+	getModule() moduleName                                       // the application or library in which the associated BO is developed
+	setModule(module moduleName)                                 // setting the module
+	getSrcPath() string                                          // source path of the associated Business Object
+	isInterface() bool                                           // tells if the class is a concrete one, or an interface
+	AsInterface() IClassCore                                     // sets the class as an interface
+	GetValueAsString(IBusinessObject, string) string             // returning a BO's field's value, given the field's name
+	SetValueAsString(IBusinessObject, string, string) error      // setting a BO's field's value, given the field's name
+	GetNumberOf(IBusinessObject, string) int                     // returning the number of BOs a multiple relationship points to
+	SetRelated(IBusinessObject, string, []IBusinessObject) error // setting the BOs a relationship points to, once loaded
 }
 
 // An internal struct that should implement IClassCore
@@ -86,6 +88,14 @@ func (thisCore *classCore) SetValueAsString(IBusinessObject, string, string) err
 	panic("SetValueAsString has to be implemented by a concrete Class__UTILS__ object")
 }
 
+func (thisCore *classCore) GetNumberOf(IBusinessObject, string) int {
+	panic("GetNumberOf has to be implemented by a concrete Class__UTILS__ object")
+}
+
+func (thisCore *classCore) SetRelated(IBusinessObject, string, []IBusinessObject) error {
+	panic("SetRelated has to be implemented by a concrete Class__UTILS__ object")
+}
+
 // ------------------------------------------------------------------------------------------------
 // Defining and registering classes
 // ------------------------------------------------------------------------------------------------
@@ -181,6 +191,11 @@ func specsForName(clsName className) IBusinessObjectSpecs {
 	return specsRegistry.items[clsName]
 }
 
+// the specs of the given BO's class, if registered
+func specsOf(bObj IBusinessObject) IBusinessObjectSpecs {
+	return specsForName(className(utils.TypeNameOf(bObj, true)))
+}
+
 // ------------------------------------------------------------------------------------------------
 // Endpoints registry
 // ------------------------------------------------------------------------------------------------
@@ -0,0 +1,75 @@
+package goald
+
+import (
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// registers the given specs - and class, if any - for the duration of the test
+func registerTestClass(t *testing.T, name className, specs IBusinessObjectSpecs, class IClass) {
+	t.Helper()
+
+	RegisterSpecs(name, specs)
+	if class != nil {
+		In("test").Register(class)
+	}
+
+	t.Cleanup(func() {
+		specsRegistry.mx.Lock()
+		delete(specsRegistry.items, name)
+		specsRegistry.mx.Unlock()
+
+		classRegistry.mx.Lock()
+		delete(classRegistry.items, name)
+		classRegistry.mx.Unlock()
+	})
+}
+
+// isolates the endpoints declared during the test from the ones declared elsewhere
+func useTestEndpoints(t *testing.T) {
+	t.Helper()
+
+	restRegistry.mx.Lock()
+	previous := restRegistry.endpoints
+	restRegistry.endpoints = nil
+	restRegistry.mx.Unlock()
+
+	t.Cleanup(func() {
+		restRegistry.mx.Lock()
+		restRegistry.endpoints = previous
+		restRegistry.mx.Unlock()
+	})
+}
+
+// mounts all the endpoints declared during the test on a new router, under "/api", as the server does
+func mountTestEndpoints(t *testing.T) (router *httprouter.Router) {
+	t.Helper()
+
+	defer func() {
+		if panicked := recover(); panicked != nil {
+			t.Fatalf("Could not mount the endpoints: %v", panicked)
+		}
+	}()
+
+	testServer := &server{router: httprouter.New()}
+	testServer.initAPIRoutes("/api")
+
+	return testServer.router
+}
+
+// checks that the given request is routed, with the expected path params
+func checkTestRoute(t *testing.T, router *httprouter.Router, method, path string, expectedParams map[string]string) {
+	t.Helper()
+
+	handle, params, _ := router.Lookup(method, path)
+	if handle == nil {
+		t.Fatalf("No route found for %s %s", method, path)
+	}
+
+	for name, expected := range expectedParams {
+		if got := params.ByName(name); got != expected {
+			t.Errorf("%s %s: param '%s' = '%s', expected '%s'", method, path, name, got, expected)
+		}
+	}
+}
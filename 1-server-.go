@@ -59,6 +59,9 @@ func NewServer() ServerContext {
 		server.runCodeGen(srcdir, codeGenLevel(codegen), webdir, nativedir, regen, bindir)
 	}
 
+	// linking each class to the one it inherits from, now that they're all registered
+	initClassHierarchies()
+
 	// performing some checks on the code - but only in dev mode of course
 	if server.IsDev() {
 		server.runCodeChecks()
@@ -69,6 +72,11 @@ func NewServer() ServerContext {
 		initAndRegisterDB(dbConfig)
 	}
 
+	// initialising the storage of the attached files - unless the app has set its own one
+	if storageConfig := serverConfig.commonPart().FileStorage; storageConfig != nil && defaultFileStorage == nil {
+		SetDefaultFileStorage(NewLocalFileStorage(storageConfig.LocalDir))
+	}
+
 	// bit of logging // TODO remove
 	slog.Info(fmt.Sprintf("Instance: %s", server.instance))
 
@@ -109,14 +117,17 @@ func (thisServer *server) initRoutes() {
 	// configuring & adding the REST API endpoints - should we have to serve an API
 	apiPath := thisServer.config.commonPart().HTTP.ApiPath
 	if apiPath != "" {
-		for _, endpoint := range restRegistry.endpoints {
-			slog.Info(fmt.Sprintf("Serving: %s %s", endpoint.getMethod(), apiPath+endpoint.getFullPath()))
-			thisServer.router.Handle(endpoint.getMethod(), apiPath+endpoint.getFullPath(), thisServer.handleFor(endpoint))
-		}
+		thisServer.initAPIRoutes(apiPath)
 	} else {
 		core.PanicMsg("No path provided for the API!")
 	}
 
+	// serving the OpenAPI document describing the API, if configured
+	if openAPIPath := thisServer.config.commonPart().HTTP.OpenAPIPath; openAPIPath != "" {
+		slog.Info(fmt.Sprintf("Serving: %s %s", http.MethodGet, openAPIPath))
+		thisServer.router.HandlerFunc(http.MethodGet, openAPIPath, thisServer.handleOpenAPIDocument())
+	}
+
 	// configuring the static routes TODO not used for now
 	// for _, route := range thisServer.config.commonPart().HTTP.StaticRoutes {
 	// 	if fileToServe := route.ServeFile; fileToServe != "" {
@@ -174,6 +185,9 @@ func (thisServer *server) Start() {
 
 	// TODO set router PanicHandler
 
+	// the expired idempotent requests are regularly purged
+	thisServer.startIdempotencyPurges()
+
 	// listening to HTTP requests (blocking process)
 	port := thisServer.config.commonPart().HTTP.Port
 	addr := fmt.Sprintf(":%d", port)
@@ -0,0 +1,220 @@
+// ------------------------------------------------------------------------------------------------
+// Here we handle the conditional requests on the endpoints declared as such: the GET responses
+// carry an ETag - and a last modification date when known - so that a client can avoid getting
+// again what it already has, or overwriting a BO someone else has changed in the meantime
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/hstatus"
+)
+
+const (
+	eTagHEADER            = "ETag"
+	lastModifiedHEADER    = "Last-Modified"
+	ifNoneMatchHEADER     = "If-None-Match"
+	ifModifiedSinceHEADER = "If-Modified-Since"
+	ifMatchHEADER         = "If-Match"
+)
+
+// the entity tag of the given BO: its version, if its class keeps track of it, or else a hash of its persisted values,
+// in which case the BO must have been fully loaded; it can then be used to check the BO's current state before a write
+func getBOETag(boSpecs IBusinessObjectSpecs, bObj IBusinessObject) string {
+	class := getClass(boSpecs)
+
+	if versionField := boSpecs.base().versionField; versionField != nil {
+		return `"` + string(bObj.GetID()) + "-" + class.GetValueAsString(bObj, versionField.getName()) + `"`
+	}
+
+	hash := sha256.New()
+	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
+		hash.Write([]byte(field.getName() + "=" + class.GetValueAsString(bObj, field.getName()) + "\n"))
+	}
+	for _, relationship := range boSpecs.base().relationshipsWithColumn {
+		hash.Write([]byte(relationship.getName() + "=" + class.GetValueAsString(bObj, relationship.getName()) + "\n"))
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// the last time the given BO was written, if its class keeps track of it
+func getBOLastModified(boSpecs IBusinessObjectSpecs, bObj IBusinessObject) time.Time {
+	lastModifiedField := boSpecs.base().lastModifiedField
+	if lastModifiedField == nil {
+		return time.Time{}
+	}
+
+	lastModified, errParse := time.Parse(core.RFC3339Milli, getClass(boSpecs).GetValueAsString(bObj, lastModifiedField.getName()))
+	if errParse != nil {
+		return time.Time{}
+	}
+
+	return lastModified
+}
+
+// the ETag & last modification date of the given response's content: the ones of the BO it contains, or else - e.g.
+// for a list - a weak ETag computed from the content's JSON form; no ETag for an empty content; a BO only partially
+// loaded - i.e. projected - only gets its own ETag if it's based on its version, since a hash of its values would not
+// match the one of the full BO, checked for an "If-Match" header; the weak ETag given instead never matches such a header
+func getResponseValidators(resp *response, projected bool) (eTag string, lastModified time.Time, err error) {
+	content := resp.Object
+	if content == nil {
+		content = resp.ObjectList
+	}
+
+	jsonBytes, errMarshal := json.Marshal(content)
+	if errMarshal != nil {
+		return "", time.Time{}, ErrorC(errMarshal, "Could not marshal the response's content to compute its ETag")
+	}
+
+	if string(jsonBytes) == "null" {
+		return "", time.Time{}, nil
+	}
+
+	if bObj, isBO := content.(IBusinessObject); isBO {
+		if boSpecs := specsOf(bObj); boSpecs != nil && (!projected || boSpecs.base().versionField != nil) {
+			return getBOETag(boSpecs, bObj), getBOLastModified(boSpecs, bObj), nil
+		}
+	}
+
+	hash := sha256.Sum256(jsonBytes)
+
+	return `W/"` + hex.EncodeToString(hash[:16]) + `"`, time.Time{}, nil
+}
+
+// tells if the given ETag is listed in the given "If-Match" or "If-None-Match" header value; with the weak comparison,
+// the weak ETags - i.e. prefixed with "W/" - can match, while they never match with the strong one
+func matchesETag(headerValue string, eTag string, weakComparison bool) bool {
+	for _, candidate := range strings.Split(headerValue, ",") {
+		candidate = strings.TrimSpace(candidate)
+		switch {
+		case candidate == "*":
+			return true
+		case weakComparison && strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(eTag, "W/"):
+			return true
+		case !weakComparison && candidate == eTag && !strings.HasPrefix(eTag, "W/"):
+			return true
+		}
+	}
+
+	return false
+}
+
+// sets the "ETag" & "Last-Modified" headers of a successful GET response on a conditional endpoint, and tells if the
+// client already has this state of the resource, according to the "If-None-Match" - or else "If-Modified-Since" - header
+func setValidators(webCtx *webContextImpl, req *http.Request, resp *response, w http.ResponseWriter) (notModified bool, err error) {
+	if !webCtx.ep.isConditional() || req.Method != http.MethodGet || resp.statusObj.Val() != http.StatusOK {
+		return false, nil
+	}
+
+	projected := false
+	if resourceSpecs := specsForName(webCtx.ep.getResourceClass()); resourceSpecs != nil {
+		projected = len(getProjection(resourceSpecs, webCtx.ep.getLoadingType(), webCtx.selectedFields)) > 0
+	}
+
+	eTag, lastModified, errValidators := getResponseValidators(resp, projected)
+	if errValidators != nil || eTag == "" {
+		return false, errValidators
+	}
+
+	w.Header().Set(eTagHEADER, eTag)
+	if !lastModified.IsZero() {
+		w.Header().Set(lastModifiedHEADER, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := req.Header.Get(ifNoneMatchHEADER); ifNoneMatch != "" {
+		return matchesETag(ifNoneMatch, eTag, true), nil
+	}
+
+	if ifModifiedSince := req.Header.Get(ifModifiedSinceHEADER); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, errParse := http.ParseTime(ifModifiedSince)
+
+		// the HTTP dates have no sub-second precision
+		return errParse == nil && !lastModified.Truncate(time.Second).After(since), nil
+	}
+
+	return false, nil
+}
+
+// calls the given handler; for a PUT, PATCH or DELETE request on a conditional endpoint, with an "If-Match" header, the
+// targeted BO - given in the path, or else by the input - is locked, and its current state checked against the header,
+// within a transaction that the handler's DB accesses join, so that a client does not overwrite changes it has not seen;
+// an error comes with the status to send back
+func handleIfMatch(webCtx *webContextImpl, req *http.Request, input any, resp *response, handle func()) (hstatus.Code, error) {
+	ifMatch := req.Header.Get(ifMatchHEADER)
+	if !webCtx.ep.isConditional() || ifMatch == "" ||
+		(req.Method != http.MethodPut && req.Method != http.MethodPatch && req.Method != http.MethodDelete) {
+		handle()
+		return hstatus.OK, nil
+	}
+
+	var idProp IField
+	var idPropVal string
+	if idProp = webCtx.ep.getIDProp(); idProp != nil {
+		idPropVal = webCtx.GetTargetRefOrID()
+	} else if inputBO, isBO := input.(IBusinessObject); isBO && inputBO.GetID() != "" && webCtx.GetResource() != nil {
+		idProp, idPropVal = webCtx.GetResource().ID(), string(inputBO.GetID())
+	} else {
+		return hstatus.BadRequest, Error("The %s header can only be used on a request targeting 1 '%s'",
+			ifMatchHEADER, webCtx.ep.getResourceClass())
+	}
+
+	handled := false
+	failureStatus := hstatus.InternalServerError // becomes 412 if the precondition is actually not met
+	daoCtx := webCtx.GetBloContext().GetDaoContext()
+	errTx := daoCtx.inTransaction(idProp.ownerSpecs().getInDB(), func() error {
+		// no one else can write the BO until we're done
+		if errLock := dbLockOne(daoCtx, idProp, idPropVal); errLock != nil {
+			return errLock
+		}
+
+		current, errLoad := dbLoadOne(daoCtx, idProp, idPropVal)
+		if errLoad != nil {
+			// no current state can match the header
+			var notFoundErr *NotFoundError
+			if errors.As(errLoad, &notFoundErr) {
+				failureStatus = hstatus.PreconditionFailed
+			}
+			return ErrorC(errLoad, "Could not load the targeted '%s'", webCtx.ep.getResourceClass())
+		}
+
+		if eTag := getBOETag(specsOf(current), current); !matchesETag(ifMatch, eTag, false) {
+			failureStatus = hstatus.PreconditionFailed
+			return Error("The targeted '%s' (ID = %s) has changed: its ETag is now %s",
+				webCtx.ep.getResourceClass(), current.GetID(), eTag)
+		}
+
+		handle()
+		handled = true
+
+		// nothing should be committed if the handling has failed
+		if resp.statusObj.Val() >= http.StatusMultipleChoices {
+			return Error("Could not handle the conditional request: %s", resp.Message)
+		}
+
+		return nil
+	})
+
+	switch {
+	case !handled && errTx != nil:
+		// the precondition has failed, or could not be checked
+		return failureStatus, errTx
+	case handled && resp.statusObj.Val() >= http.StatusMultipleChoices:
+		// the response already tells what's gone wrong
+		return hstatus.OK, nil
+	case errTx != nil:
+		// the commit has failed
+		return hstatus.InternalServerError, errTx
+	}
+
+	return hstatus.OK, nil
+}
@@ -0,0 +1,204 @@
+package goald
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ------------------------------------------------------------------------------------------------
+// test classes: one with a version field, one without
+// ------------------------------------------------------------------------------------------------
+
+type eTagTestItem struct {
+	BusinessObject
+	Name    string
+	Version int
+}
+
+func (thisItem *eTagTestItem) item() *eTagTestItem {
+	return thisItem
+}
+
+type eTagTestVersionedItem struct {
+	eTagTestItem
+}
+
+type eTagTestClass struct {
+	IClassCore
+}
+
+func (thisClass *eTagTestClass) GetValueAsString(bObj IBusinessObject, fieldName string) string {
+	item := bObj.(interface{ item() *eTagTestItem }).item()
+
+	switch fieldName {
+	case "ID":
+		return string(item.GetID())
+	case "Name":
+		return item.Name
+	case "Version":
+		return strconv.Itoa(item.Version)
+	}
+
+	return ""
+}
+
+func (thisClass *eTagTestClass) NewObject() any { return nil }
+func (thisClass *eTagTestClass) NewSlice() any  { return nil }
+
+// registers, for the duration of the test, the 2 test classes
+func registerETagTestClasses(t *testing.T) (specs, versionedSpecs IBusinessObjectSpecs) {
+	specs = NewBusinessObjectSpecs()
+	NewStringField(specs, "Name", false)
+	NewIntField(specs, "Version", false)
+	registerTestClass(t, "eTagTestItem", specs, &eTagTestClass{NewClassCore("", "eTagTestItem", "2026-01-01T00:00:00Z")})
+
+	versionedSpecs = NewBusinessObjectSpecs()
+	NewStringField(versionedSpecs, "Name", false)
+	versionedSpecs.SetVersionField(NewIntField(versionedSpecs, "Version", false))
+	registerTestClass(t, "eTagTestVersionedItem", versionedSpecs,
+		&eTagTestClass{NewClassCore("", "eTagTestVersionedItem", "2026-01-01T00:00:00Z")})
+
+	return
+}
+
+func newETagTestItem(id BObjID, name string, version int) *eTagTestItem {
+	item := &eTagTestItem{Name: name, Version: version}
+	item.ID = id
+
+	return item
+}
+
+func newETagTestVersionedItem(id BObjID, name string, version int) *eTagTestVersionedItem {
+	return &eTagTestVersionedItem{eTagTestItem: *newETagTestItem(id, name, version)}
+}
+
+// ------------------------------------------------------------------------------------------------
+// tests
+// ------------------------------------------------------------------------------------------------
+
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		name           string
+		headerValue    string
+		eTag           string
+		weakComparison bool
+		expected       bool
+	}{
+		{"same strong, strong comparison", `"abc"`, `"abc"`, false, true},
+		{"same strong, weak comparison", `"abc"`, `"abc"`, true, true},
+		{"other strong", `"abc"`, `"abd"`, false, false},
+		{"weak candidate, strong comparison", `W/"abc"`, `"abc"`, false, false},
+		{"weak ETag, strong comparison", `"abc"`, `W/"abc"`, false, false},
+		{"weak candidate, weak comparison", `W/"abc"`, `"abc"`, true, true},
+		{"weak ETag, weak comparison", `"abc"`, `W/"abc"`, true, true},
+		{"both weak, weak comparison", `W/"abc"`, `W/"abc"`, true, true},
+		{"both weak, strong comparison", `W/"abc"`, `W/"abc"`, false, false},
+		{"in a list", `"xyz", "abc"`, `"abc"`, false, true},
+		{"in a list without spaces", `"xyz","abc"`, `"abc"`, false, true},
+		{"not in a list", `"xyz", "uvw"`, `"abc"`, false, false},
+		{"wildcard, strong comparison", `*`, `"abc"`, false, true},
+		{"wildcard, weak comparison", ` * `, `W/"abc"`, true, true},
+		{"unquoted candidate", `abc`, `"abc"`, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesETag(tt.headerValue, tt.eTag, tt.weakComparison); got != tt.expected {
+				t.Errorf("matchesETag(%s, %s, %t) = %t, expected %t", tt.headerValue, tt.eTag, tt.weakComparison, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetBOETag(t *testing.T) {
+	eTagTestSpecs, eTagTestVersionedSpecs := registerETagTestClasses(t)
+
+	isHashETag := func(eTag string) bool {
+		return len(eTag) == 34 && strings.HasPrefix(eTag, `"`) && strings.HasSuffix(eTag, `"`)
+	}
+
+	tests := []struct {
+		name     string
+		specs    IBusinessObjectSpecs
+		bObj     IBusinessObject
+		other    IBusinessObject // if set, a BO expected to have the same ETag - or not
+		sameETag bool
+		expected string // if set, the exact ETag expected
+	}{
+		{"versioned", eTagTestVersionedSpecs, newETagTestVersionedItem("12", "a", 3), nil, false, `"12-3"`},
+		{"versioned, initial version", eTagTestVersionedSpecs, newETagTestVersionedItem("7", "a", 0), nil, false, `"7-0"`},
+		{"versioned, other values", eTagTestVersionedSpecs, newETagTestVersionedItem("12", "a", 3),
+			newETagTestVersionedItem("12", "b", 3), true, ""},
+		{"versioned, other version", eTagTestVersionedSpecs, newETagTestVersionedItem("12", "a", 3),
+			newETagTestVersionedItem("12", "a", 4), false, ""},
+		{"hashed, same values", eTagTestSpecs, newETagTestItem("12", "a", 3),
+			newETagTestItem("12", "a", 3), true, ""},
+		{"hashed, other name", eTagTestSpecs, newETagTestItem("12", "a", 3),
+			newETagTestItem("12", "b", 3), false, ""},
+		{"hashed, other ID", eTagTestSpecs, newETagTestItem("12", "a", 3),
+			newETagTestItem("13", "a", 3), false, ""},
+		{"hashed, values not mixed up", eTagTestSpecs, newETagTestItem("1", "2", 3),
+			newETagTestItem("12", "", 3), false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eTag := getBOETag(tt.specs, tt.bObj)
+
+			if tt.expected != "" && eTag != tt.expected {
+				t.Errorf("getBOETag() = %s, expected %s", eTag, tt.expected)
+			}
+
+			if tt.specs.base().versionField == nil && !isHashETag(eTag) {
+				t.Errorf("getBOETag() = %s, expected a strong ETag with a 32-character hash", eTag)
+			}
+
+			if tt.other != nil {
+				if otherETag := getBOETag(tt.specs, tt.other); (otherETag == eTag) != tt.sameETag {
+					t.Errorf("getBOETag() = %s & %s, expected them to be equal: %t", eTag, otherETag, tt.sameETag)
+				}
+			}
+		})
+	}
+}
+
+func TestGetResponseValidators(t *testing.T) {
+	registerETagTestClasses(t)
+
+	tests := []struct {
+		name      string
+		resp      *response
+		projected bool
+		expected  string // "none", "strong" or "weak"
+	}{
+		{"no content", &response{}, false, "none"},
+		{"BO", &response{Object: newETagTestItem("12", "a", 3)}, false, "strong"},
+		{"projected BO", &response{Object: newETagTestItem("12", "a", 3)}, true, "weak"},
+		{"versioned BO", &response{Object: newETagTestVersionedItem("12", "a", 3)}, false, "strong"},
+		{"projected versioned BO", &response{Object: newETagTestVersionedItem("12", "a", 3)}, true, "strong"},
+		{"list", &response{ObjectList: []*eTagTestItem{newETagTestItem("12", "a", 3)}}, false, "weak"},
+		{"not a BO", &response{Object: map[string]int{"count": 3}}, false, "weak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eTag, _, err := getResponseValidators(tt.resp, tt.projected)
+			if err != nil {
+				t.Fatalf("getResponseValidators() error = %v", err)
+			}
+
+			got := "none"
+			switch {
+			case strings.HasPrefix(eTag, `W/"`):
+				got = "weak"
+			case strings.HasPrefix(eTag, `"`):
+				got = "strong"
+			}
+
+			if got != tt.expected {
+				t.Errorf("getResponseValidators() ETag = %s, expected a %s one", eTag, tt.expected)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package goald
 import (
 	"encoding/json"
 	"os"
+	"time"
 
 	core "github.com/aldesgroup/corego"
 	"sigs.k8s.io/yaml"
@@ -37,6 +38,7 @@ type serverConfig struct {
 	HTTP        *httpConfig
 	Databases   []*dbConfig
 	DataLoaders map[string]map[string]string
+	FileStorage *fileStorageConfig
 
 	// technical props
 	envAsType envType
@@ -45,17 +47,29 @@ type serverConfig struct {
 type DatabaseID string
 
 type httpConfig struct {
-	Port         int
-	ApiPath      string
-	StaticRoutes []*staticRouteConfig
+	Port           int
+	ApiPath        string
+	OpenAPIPath    string // if set, the OpenAPI document describing the API is served at this path, e.g. "/openapi.json"
+	IdempotencyTTL string // how long the responses of the idempotent requests can be replayed, e.g. "48h"; 24 hours by default
+	StaticRoutes   []*staticRouteConfig
+
+	// technical props
+	idempotencyTTL time.Duration
 }
 
+// how long the responses of the idempotent requests are kept by default
+const defaultIdempotencyTTL = 24 * time.Hour
+
 type staticRouteConfig struct {
 	For       string
 	ServeFile string
 	ServeDir  string
 }
 
+type fileStorageConfig struct {
+	LocalDir string // the directory where the attached files are stored
+}
+
 type dbConfig struct {
 	DbID      DatabaseID
 	DbType    databaseType
@@ -108,6 +122,19 @@ func readAndCheckConfig(fromPath string) IServerConfig {
 			config.Env)
 	}
 
+	// checking how long the responses of the idempotent requests are kept
+	if config.HTTP != nil {
+		config.HTTP.idempotencyTTL = defaultIdempotencyTTL
+		if config.HTTP.IdempotencyTTL != "" {
+			ttl, errParse := time.ParseDuration(config.HTTP.IdempotencyTTL)
+			core.PanicMsgIfErr(errParse, "the 'HTTP.IdempotencyTTL' config item (\"%s\") is not a valid duration, e.g. \"48h\"",
+				config.HTTP.IdempotencyTTL)
+			core.PanicMsgIf(ttl <= 0, "the 'HTTP.IdempotencyTTL' config item (\"%s\") should be a positive duration",
+				config.HTTP.IdempotencyTTL)
+			config.HTTP.idempotencyTTL = ttl
+		}
+	}
+
 	return configObj
 }
 
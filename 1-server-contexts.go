@@ -7,6 +7,11 @@
 // ------------------------------------------------------------------------------------------------
 package goald
 
+import (
+	"fmt"
+	"log/slog"
+)
+
 // ------------------------------------------------------------------------------------------------
 // AppContext contains the minimal info set that should be accessible in all the layers of the app
 // ------------------------------------------------------------------------------------------------
@@ -42,12 +47,38 @@ type bloContextImpl struct {
 }
 
 func (thisBloCtx *bloContextImpl) GetDaoContext() DaoContext {
-	return thisBloCtx.daoContext // TODO instantiate
+	// initialising it when first needed, so all the DB accesses of the current request share the same transactions
+	if thisBloCtx.daoContext == nil {
+		thisBloCtx.daoContext = newDaoContext(thisBloCtx.httpRequestContext.server)
+	}
+
+	return thisBloCtx.daoContext
 }
 
-// the server is its own DAo context
+// the server, being shared by all the data loaders, provides a new DAO context each time; see withSameDaoContext
 func (thisServer *server) GetDaoContext() DaoContext {
-	return thisServer
+	return newDaoContext(thisServer)
+}
+
+// a BLO context bound to 1 DAO context, whatever the DAO context of the wrapped BLO context
+type boundBloContext struct {
+	BloContext
+	daoContext DaoContext
+}
+
+func (thisBloCtx *boundBloContext) GetDaoContext() DaoContext {
+	return thisBloCtx.daoContext
+}
+
+// returns the given BLO context's DAO context, along with a BLO context always returning this DAO context - which is
+// not the case of the server - so that the hooks called within a generic BLO operation join its transactions
+func withSameDaoContext(bloCtx BloContext) (BloContext, DaoContext) {
+	daoCtx := bloCtx.GetDaoContext()
+	if bloCtx.GetDaoContext() == daoCtx {
+		return bloCtx, daoCtx
+	}
+
+	return &boundBloContext{BloContext: bloCtx, daoContext: daoCtx}, daoCtx
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -62,6 +93,66 @@ type ServerContext interface {
 // DaoContext should contain the necessary info for handling database access
 type DaoContext interface {
 	iRestContext
+	getExecutor(db *DB) dbExecutor               // the transaction currently opened on the given DB, else the DB itself
+	inTransaction(db *DB, fn func() error) error // runs the given function within a transaction on the given DB
+}
+
+// default implementation for DAO context
+type daoContextImpl struct {
+	*server                             // proxying the server
+	transactions map[*DB]*dbTransaction // the transactions currently opened through this context, per DB
+}
+
+func newDaoContext(server *server) *daoContextImpl {
+	return &daoContextImpl{
+		server:       server,
+		transactions: map[*DB]*dbTransaction{},
+	}
+}
+
+func (thisDaoCtx *daoContextImpl) getExecutor(db *DB) dbExecutor {
+	if tx := thisDaoCtx.transactions[db]; tx != nil {
+		return tx
+	}
+
+	return db
+}
+
+// if a transaction is already opened on the given DB, then we're just joining it; else, a new one is opened,
+// then committed if the function returns no error, and rolled back otherwise
+func (thisDaoCtx *daoContextImpl) inTransaction(db *DB, fn func() error) (err error) {
+	if db == nil || thisDaoCtx.transactions[db] != nil {
+		return fn()
+	}
+
+	tx, errBegin := db.begin()
+	if errBegin != nil {
+		return ErrorC(errBegin, "Could not start a transaction on DB '%s'", db.config.DbID)
+	}
+	thisDaoCtx.transactions[db] = tx
+
+	defer func() {
+		delete(thisDaoCtx.transactions, db)
+
+		// panicking or failing means rolling back
+		if panicked := recover(); panicked != nil || err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				slog.Error(fmt.Sprintf("Could not rollback a transaction on DB '%s': %s", db.config.DbID, errRollback))
+			}
+
+			if panicked != nil {
+				panic(panicked)
+			}
+
+			return
+		}
+
+		if errCommit := tx.Commit(); errCommit != nil {
+			err = ErrorC(errCommit, "Could not commit a transaction on DB '%s'", db.config.DbID)
+		}
+	}()
+
+	return fn()
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -70,8 +161,14 @@ type WebContext interface {
 	iRestContext
 	GetBloContext() BloContext
 	GetTargetRefOrID() string
+	GetParentID() string                 // the ID of the parent BO, for an endpoint nested under its parent's route, e.g. "/orders/:ID/lines"
 	GetResource() IBusinessObjectSpecs   // the class of the resource being requested
 	GetResourceLoadingType() LoadingType // returns the loading type of the current main resources (BOs) being worked on
+	GetSelectedFields() []string         // the properties asked for with the "fields" query param, if any, e.g. "?fields=Name,Email"
+	SetResponseDetails(details any)      // to give more details in the response, like the reasons why the input is not valid
+
+	// private methods
+	getParentRelationship() *Relationship // the relationship from the resources to their parent, for a nested endpoint
 }
 
 // default implementation for web context
@@ -80,20 +177,24 @@ type webContextImpl struct {
 	*httpRequestContext // wrapping one of the server's children handling 1 request
 	ep                  iEndpoint
 	resource            IBusinessObjectSpecs
-	targetRefOrID       string // the ID or ref, or whatever property value used to clearly identify a resource
-	inputBodyBytes      []byte // keeping track of the incoming request body
+	targetRefOrID       string   // the ID or ref, or whatever property value used to clearly identify a resource
+	parentID            string   // the ID of the parent BO, if the endpoint is nested under its parent's route
+	selectedFields      []string // the properties asked for with the "fields" query param, if any
+	inputBodyBytes      []byte   // keeping track of the incoming request body
 	bloContext          BloContext
+	responseDetails     any // some more details to give in the response, if any
 }
 
 // type check
 var _ WebContext = (*webContextImpl)(nil)
 
-func newWebContext(reqCtx *httpRequestContext, ep iEndpoint, targetRefOrID string) *webContextImpl {
+func newWebContext(reqCtx *httpRequestContext, ep iEndpoint, targetRefOrID string, parentID string) *webContextImpl {
 	return &webContextImpl{
 		appContextImpl:     &appContextImpl{},
 		httpRequestContext: reqCtx,
 		ep:                 ep,
 		targetRefOrID:      targetRefOrID,
+		parentID:           parentID,
 	}
 }
 
@@ -113,7 +214,7 @@ func (thisWebCtx *webContextImpl) GetBloContext() BloContext {
 
 func (thisWebCtx *webContextImpl) GetResource() IBusinessObjectSpecs {
 	if thisWebCtx.resource == nil {
-		thisWebCtx.resource = specsRegistry.items[thisWebCtx.ep.getInputOrParamsClass()]
+		thisWebCtx.resource = specsRegistry.items[thisWebCtx.ep.getResourceClass()]
 	}
 
 	return thisWebCtx.resource
@@ -123,6 +224,22 @@ func (thisWebCtx *webContextImpl) GetTargetRefOrID() string {
 	return thisWebCtx.targetRefOrID
 }
 
+func (thisWebCtx *webContextImpl) GetParentID() string {
+	return thisWebCtx.parentID
+}
+
+func (thisWebCtx *webContextImpl) getParentRelationship() *Relationship {
+	return thisWebCtx.ep.getParentRelationship()
+}
+
 func (thisWebCtx *webContextImpl) GetResourceLoadingType() LoadingType {
 	return thisWebCtx.ep.getLoadingType()
 }
+
+func (thisWebCtx *webContextImpl) GetSelectedFields() []string {
+	return thisWebCtx.selectedFields
+}
+
+func (thisWebCtx *webContextImpl) SetResponseDetails(details any) {
+	thisWebCtx.responseDetails = details
+}
@@ -5,11 +5,15 @@ package goald
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
 
+	core "github.com/aldesgroup/corego"
 	"github.com/aldesgroup/goald/features/hstatus"
 	r "github.com/julienschmidt/httprouter"
 )
@@ -18,8 +22,6 @@ import (
 // Serving the REST endpoints
 // ------------------------------------------------------------------------------------------------
 
-// TODO handle patching BOs with safeguards, like authorizing a limited list of fields (on the class for instance)
-
 var reqCount int // to remove
 
 func (thisServer *server) ServeEndpoint(ep iEndpoint, w http.ResponseWriter, req *http.Request, params r.Params) {
@@ -46,6 +48,7 @@ type response struct {
 	StatusCode int          `json:"StatusCode"`
 	Status     string       `json:"Status"`
 	Message    string       `json:"Message"`
+	Details    any          `json:"Details,omitempty"`
 }
 
 func errResp(_ int, _ string, _ ...any) *response {
@@ -60,23 +63,69 @@ func (thisReqCtx *httpRequestContext) serve(ep iEndpoint, w http.ResponseWriter,
 	slog.Info(fmt.Sprintf("[%s] Serving %s %s: %s", prefix, ep.getMethod(), ep.getFullPath(), ep.getLabel()))
 
 	// initialising the web context that's going to be passed to the applicative handler
-	var targetRefOrID string
+	var targetRefOrID, parentID string
 	if ep.getIDProp() != nil {
-		targetRefOrID = params.ByName(ep.getIDProp().getName())
+		targetRefOrID = params.ByName(ep.getIDParam())
+	}
+	if ep.getParentRelationship() != nil {
+		parentID = params.ByName(ep.getParentIDParam())
 	}
 
 	// prepping the context that's going to contain all the input data
 	// + some of the current endpoint's config
-	webCtx := newWebContext(thisReqCtx, ep, targetRefOrID)
+	webCtx := newWebContext(thisReqCtx, ep, targetRefOrID, parentID)
 
 	// prepping the response
 	resp := &response{}
 
+	// warning the client if this endpoint should not be used anymore
+	setDeprecationHeaders(ep, w)
+
 	// TODO check auth!
 
+	// a nested endpoint can only target a BO belonging to the parent given in the path
+	if status, errParent := checkParentOfTarget(webCtx); errParent != nil {
+		thisReqCtx.write(&response{statusObj: status, Message: errParent.Error()}, w)
+		return
+	}
+
+	// a GET request can ask for only some of the resource's properties
+	selectedFields, fieldErrors := retrieveSelectedFields(req, ep)
+	if len(fieldErrors) > 0 {
+		thisReqCtx.write(&response{statusObj: hstatus.BadRequest, Message: "Some of the requested fields cannot be selected",
+			Details: fieldErrors}, w)
+		return
+	}
+	webCtx.selectedFields = selectedFields
+
+	// sending back a file is a different story, since there's no JSON response in this case
+	if ep.isFileDownload() {
+		thisReqCtx.sendFile(ep, webCtx, w)
+		return
+	}
+
 	// checking the input
 	var input any
-	if ep.hasBodyOrParamsInput() {
+	if ep.isFileUpload() {
+		var inputErr error
+		if input, inputErr = retrieveUploadedFile(w, req, ep.getMaxUploadSize()); inputErr != nil {
+			var maxBytesErr *http.MaxBytesError
+			resp.statusObj = core.IfThenElse(errors.As(inputErr, &maxBytesErr), hstatus.RequestEntityTooLarge, hstatus.BadRequest)
+			resp.Message = fmt.Sprintf("Bad file upload (%s)", inputErr)
+
+			goto End
+		}
+
+		defer closeFileContent(input.(*AttachedFile))
+	} else if ep.isPatch() {
+		var inputErr error
+		if input, inputErr = retrievePatch(req, webCtx); inputErr != nil {
+			resp.statusObj = hstatus.BadRequest
+			resp.Message = fmt.Sprintf("Bad patch in request body (%s)", inputErr)
+
+			goto End
+		}
+	} else if ep.hasBodyOrParamsInput() {
 		var inputErr error
 		if ep.isBodyInputRequired() {
 			if input, inputErr = retrieveInputData(req, webCtx, ep); inputErr != nil {
@@ -95,11 +144,75 @@ func (thisReqCtx *httpRequestContext) serve(ep iEndpoint, w http.ResponseWriter,
 		}
 	}
 
+	// a retried idempotent request is not handled again: the response given the 1st time is replayed
+	if idemReq, errIdem := newIdempotentRequest(webCtx, req, thisReqCtx.config.commonPart().HTTP.idempotencyTTL); errIdem != nil {
+		resp.statusObj = hstatus.BadRequest
+		resp.Message = errIdem.Error()
+
+		goto End
+	} else if idemReq != nil {
+		kept, statusIdem, errStart := idemReq.start()
+		if errStart != nil {
+			resp.statusObj = statusIdem
+			resp.Message = errStart.Error()
+
+			goto End
+		}
+
+		if kept != nil {
+			kept.replay(w)
+			return
+		}
+
+		// keeping the response once it's been written
+		defer idemReq.finish(resp)
+	}
+
 	// TODO do better - some "logging"
 	slog.Debug(fmt.Sprintf("Body: %s", string(webCtx.inputBodyBytes)))
 
-	// calling the endpoint's handler, which depends on its type
-	if ep.hasBodyOrParamsInput() {
+	// calling the endpoint's handler; a conditional write must be done on the state of the targeted BO known by the
+	// client, so the handler is then called within the transaction where this state is checked
+	if statusIfMatch, errIfMatch := handleIfMatch(webCtx, req, input, resp, func() { callHandler(ep, webCtx, input, resp) }); errIfMatch != nil {
+		resp.Object, resp.ObjectList = nil, nil
+		resp.statusObj = statusIfMatch
+		resp.Message = errIfMatch.Error()
+
+		goto End
+	}
+
+	// the handler may have given some more details
+	resp.Details = webCtx.responseDetails
+
+	// no need to send back what the client already has
+	if notModified, errValidators := setValidators(webCtx, req, resp, w); errValidators != nil {
+		resp.Object, resp.ObjectList = nil, nil
+		resp.statusObj = hstatus.InternalServerError
+		resp.Message = fmt.Sprintf("Could not compute the response's ETag (%s)", errValidators)
+	} else if notModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// only sending back the selected properties, if any
+	if errProject := projectResponse(webCtx, req, resp); errProject != nil {
+		resp.Object, resp.ObjectList = nil, nil
+		resp.statusObj = hstatus.InternalServerError
+		resp.Message = fmt.Sprintf("Could not project the response (%s)", errProject)
+	}
+
+End:
+	// writing out the response
+	thisReqCtx.write(resp, w)
+}
+
+// calling the endpoint's handler, which depends on its type
+func callHandler(ep iEndpoint, webCtx *webContextImpl, input any, resp *response) {
+	if ep.isFileUpload() {
+		resp.Object, resp.statusObj, resp.Message = ep.returnOneForFile(webCtx, input.(*AttachedFile))
+	} else if ep.isPatch() {
+		resp.Object, resp.statusObj, resp.Message = ep.returnOneForPatch(webCtx, input.(Patch))
+	} else if ep.hasBodyOrParamsInput() {
 		if ep.isMultipleOutput() {
 			if ep.isMultipleInput() {
 				resp.ObjectList, resp.statusObj, resp.Message = ep.returnManyForMany(webCtx, input)
@@ -120,10 +233,6 @@ func (thisReqCtx *httpRequestContext) serve(ep iEndpoint, w http.ResponseWriter,
 			resp.Object, resp.statusObj, resp.Message = ep.returnOne(webCtx)
 		}
 	}
-
-End:
-	// writing out the response
-	thisReqCtx.write(resp, w)
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -197,6 +306,103 @@ func retrieveInputData(request *http.Request, webContext *webContextImpl, ep iEn
 	}
 }
 
+// parsing the request's body to get the partial JSON document expected to patch a BO
+func retrievePatch(request *http.Request, webContext *webContextImpl) (Patch, error) {
+	inputBodyBytes, readErr := io.ReadAll(request.Body)
+	if readErr != nil {
+		return nil, ErrorC(readErr, "Could not read request body!")
+	}
+
+	if len(inputBodyBytes) == 0 {
+		return nil, Error("Request body is empty")
+	}
+
+	// keeping track of the raw body
+	webContext.inputBodyBytes = inputBodyBytes
+
+	patch := Patch{}
+	if jsonErr := json.Unmarshal(inputBodyBytes, &patch); jsonErr != nil {
+		return nil, ErrorC(jsonErr, "Could not unmarshall the JSON patch!")
+	}
+
+	return patch, nil
+}
+
+// sending back the file returned by the endpoint's handler, or a JSON response if there's none
+func (thisReqCtx *httpRequestContext) sendFile(ep iEndpoint, webCtx WebContext, w http.ResponseWriter) {
+	file, statusObj, message := ep.returnFile(webCtx)
+	if file == nil {
+		thisReqCtx.write(&response{statusObj: statusObj, Message: message}, w)
+		return
+	}
+
+	defer closeFileContent(file)
+
+	// setting headers must be the first thing done before writing anything
+	w.Header().Set("Content-Type", core.IfThenElse(file.ContentType != "", file.ContentType, defaultCONTENTxTYPE))
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	w.WriteHeader(statusObj.Val())
+
+	if _, errCopy := io.Copy(w, file.Content); errCopy != nil {
+		// TODO change logging
+		slog.Error(fmt.Sprintf("Error while writing out the file '%s': %s", file.Name, errCopy))
+	}
+}
+
+const (
+	uploadFORMxKEY      = "file"                     // the multipart form's key for the uploaded file
+	uploadMAXxMEMORY    = 32 << 20                   // beyond this size, the uploaded files are kept on disk while being handled
+	uploadFORMxOVERHEAD = 64 << 10                   // what a multipart form may weigh, on top of the file it contains
+	defaultCONTENTxTYPE = "application/octet-stream" // when we know nothing about the file's content
+)
+
+// parsing the request's multipart form to get the uploaded file, along with its declared metadata
+func retrieveUploadedFile(w http.ResponseWriter, request *http.Request, maxSize int64) (*AttachedFile, error) {
+	// not reading - nor spooling onto the disk - more than allowed
+	if maxSize > 0 {
+		request.Body = http.MaxBytesReader(w, request.Body, maxSize+uploadFORMxOVERHEAD)
+	}
+
+	if errParse := request.ParseMultipartForm(uploadMAXxMEMORY); errParse != nil {
+		return nil, ErrorC(errParse, "Could not parse the multipart form")
+	}
+
+	content, header, errFile := request.FormFile(uploadFORMxKEY)
+	if errFile != nil {
+		return nil, ErrorC(errFile, "Could not find a file under the '%s' key", uploadFORMxKEY)
+	}
+
+	if maxSize > 0 && header.Size > maxSize {
+		if errClose := content.Close(); errClose != nil {
+			slog.Error(fmt.Sprintf("Error while closing the file '%s': %s", header.Filename, errClose))
+		}
+
+		return nil, ErrorC(&http.MaxBytesError{Limit: maxSize}, "The file '%s' is too big", header.Filename)
+	}
+
+	// getting rid of the potential parameters, like the charset
+	contentType, _, errType := mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if errType != nil {
+		contentType = defaultCONTENTxTYPE
+	}
+
+	return &AttachedFile{
+		Attachment: &Attachment{
+			Name:        header.Filename,
+			Size:        header.Size,
+			ContentType: contentType,
+		},
+		Content: content,
+	}, nil
+}
+
+func closeFileContent(file *AttachedFile) {
+	if errClose := file.Content.Close(); errClose != nil {
+		slog.Error(fmt.Sprintf("Error while closing the file '%s': %s", file.Name, errClose))
+	}
+}
+
 // parsing the request's URL to build the expected URLQueryParams object
 func retrieveURLParams(request *http.Request, _ *webContextImpl, ep iEndpoint) (any, error) {
 	// getting the right class utils
@@ -0,0 +1,247 @@
+// ------------------------------------------------------------------------------------------------
+// Here we handle the idempotent endpoints: a request sent with an "Idempotency-Key" header is
+// handled only once, its response being kept in a goald-managed table, so that it can be
+// replayed when the client retries the request, e.g. after a network failure
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/hstatus"
+)
+
+const (
+	idempotencyKeyHEADER      = "Idempotency-Key"     // the header through which a client identifies a request it may retry
+	idempotentReplayedHEADER  = "Idempotent-Replayed" // the header telling the client it gets the response of a previous request
+	idempotencyKeyMAXxLENGTH  = 255                   // the keys are meant to be UUIDs, or the like
+	idempotencySTATUSxPENDING = 0                     // the status of a request still being handled
+	idempotencyPENDINGxLEASE  = time.Minute           // beyond this, a request still pending is deemed lost, e.g. with the process handling it
+	idempotencyPURGExPERIOD   = time.Hour             // how often - at most - the expired requests are purged
+)
+
+// the columns of the idempotency table, the ID coming 1st
+var idempotencyCOLUMNS = []string{idempotencyID, idempotencyFINGERPRINT, idempotencySTATUS, idempotencyRESPONSE, idempotencyCREATION}
+
+// the DB where the idempotent requests on the given endpoint are kept: the one of its resources
+func getIdempotencyDB(ep iEndpoint) *DB {
+	if resourceSpecs := specsForName(ep.getResourceClass()); resourceSpecs != nil {
+		return resourceSpecs.getInDB()
+	}
+
+	return nil
+}
+
+// true if some idempotent endpoints keep their requests in the given DB
+func hasIdempotentEndpoints(db *DB) bool {
+	for _, ep := range restRegistry.endpoints {
+		if ep.isIdempotent() && getIdempotencyDB(ep) == db {
+			return true
+		}
+	}
+
+	return false
+}
+
+// a request on an idempotent endpoint, sent with an idempotency key
+type idempotentRequest struct {
+	db          *DB
+	id          string        // the endpoint's route, along with the key given by the client
+	fingerprint string        // a hash of the request's method, URL & body, to make sure the key is not reused for another request
+	ttl         time.Duration // how long the response is kept
+	creation    time.Time     // when the request was 1st received
+}
+
+// the request to keep track of, if the endpoint is idempotent and the client has given an idempotency key; the body
+// must have been read already
+func newIdempotentRequest(webCtx *webContextImpl, req *http.Request, ttl time.Duration) (*idempotentRequest, error) {
+	key := req.Header.Get(idempotencyKeyHEADER)
+	if !webCtx.ep.isIdempotent() || key == "" {
+		return nil, nil
+	}
+
+	if len(key) > idempotencyKeyMAXxLENGTH {
+		return nil, Error("The %s header cannot be longer than %d characters", idempotencyKeyHEADER, idempotencyKeyMAXxLENGTH)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + "\n" + req.URL.Path + "\n" + req.URL.RawQuery + "\n"))
+	hash.Write(webCtx.inputBodyBytes)
+
+	return &idempotentRequest{
+		db:          getIdempotencyDB(webCtx.ep),
+		id:          webCtx.ep.getMethod() + " " + webCtx.ep.getFullPath() + " " + key,
+		fingerprint: hex.EncodeToString(hash.Sum(nil)),
+		ttl:         ttl,
+		creation:    time.Now().UTC(),
+	}, nil
+}
+
+// a response kept for an idempotent request
+type idempotentResponse struct {
+	fingerprint string
+	statusCode  int
+	jsonBody    sql.NullString
+	creation    time.Time
+}
+
+// registers this request as being handled, unless it's been received before; then, if it's been fully handled, its
+// kept response is returned, to be replayed; an error comes with the status to send back
+func (thisReq *idempotentRequest) start() (*idempotentResponse, hstatus.Code, error) {
+	// the insert only fails if the request has been received before - or if the DB has an issue
+	errInsert := thisReq.insert()
+	if errInsert == nil {
+		return nil, hstatus.OK, nil
+	}
+
+	kept, errLoad := thisReq.load()
+	if errLoad != nil {
+		return nil, hstatus.InternalServerError, ErrorC(errors.Join(errInsert, errLoad), "Could not register the idempotent request")
+	}
+
+	switch {
+	case kept.creation.Before(thisReq.creation.Add(-thisReq.ttl)):
+		// the kept response has expired, even if it's not been purged yet, so this request is a new one
+		if errTakeOver := thisReq.takeOver(thisReq.creation.Add(-thisReq.ttl), false); errTakeOver != nil {
+			return nil, hstatus.Conflict, ErrorC(errTakeOver, "A request with the same %s header is being handled", idempotencyKeyHEADER)
+		}
+		return nil, hstatus.OK, nil
+	case kept.fingerprint != thisReq.fingerprint:
+		return nil, hstatus.UnprocessableEntity, Error("The %s header has already been used for another request", idempotencyKeyHEADER)
+	case kept.statusCode == idempotencySTATUSxPENDING && kept.creation.Before(thisReq.creation.Add(-idempotencyPENDINGxLEASE)):
+		// the 1st request has been lost, so this one can take its place - unless another retry has been quicker
+		if errTakeOver := thisReq.takeOver(thisReq.creation.Add(-idempotencyPENDINGxLEASE), true); errTakeOver != nil {
+			return nil, hstatus.Conflict, ErrorC(errTakeOver, "A request with the same %s header is being handled", idempotencyKeyHEADER)
+		}
+		return nil, hstatus.OK, nil
+	case kept.statusCode == idempotencySTATUSxPENDING:
+		return nil, hstatus.Conflict, Error("A request with the same %s header is still being handled", idempotencyKeyHEADER)
+	}
+
+	return kept, hstatus.OK, nil
+}
+
+// registers this request as pending
+func (thisReq *idempotentRequest) insert() error {
+	_, errInsert := thisReq.db.Exec(thisReq.db.adapter.getInsertValuesQuery(idempotencyTABLE, idempotencyCOLUMNS, 1),
+		thisReq.id, thisReq.fingerprint, idempotencySTATUSxPENDING, nil, thisReq.creation)
+
+	return errInsert
+}
+
+// replaces the registration of the same request, received before the given date - and still pending, if asked -
+// with this one's
+func (thisReq *idempotentRequest) takeOver(receivedBefore time.Time, stillPending bool) error {
+	columns, args := []string{idempotencyID}, []any{receivedBefore, thisReq.id}
+	if stillPending {
+		columns, args = append(columns, idempotencySTATUS), append(args, idempotencySTATUSxPENDING)
+	}
+
+	result, errDelete := thisReq.db.Exec(thisReq.db.adapter.getDeleteBeforeQuery(idempotencyTABLE, idempotencyCREATION, columns...), args...)
+	if errDelete != nil {
+		return ErrorC(errDelete, "Could not forget the previous idempotent request '%s'", thisReq.id)
+	}
+
+	if nbRows, errRows := result.RowsAffected(); errRows != nil || nbRows == 0 {
+		return Error("The previous idempotent request '%s' has already been taken over", thisReq.id)
+	}
+
+	return thisReq.insert()
+}
+
+// loads the response kept for this request
+func (thisReq *idempotentRequest) load() (*idempotentResponse, error) {
+	rows, errQuery := thisReq.db.Query(thisReq.db.adapter.getSelectQuery(idempotencyTABLE,
+		[]string{idempotencyFINGERPRINT, idempotencySTATUS, idempotencyRESPONSE, idempotencyCREATION}, idempotencyID, 1), thisReq.id)
+	if errQuery != nil {
+		return nil, ErrorC(errQuery, "Could not query the idempotent request")
+	}
+
+	defer func() {
+		if errClose := rows.Close(); errClose != nil {
+			slog.Error(fmt.Sprintf("Could not close the rows of the idempotent request: %s", errClose))
+		}
+	}()
+
+	if !rows.Next() {
+		return nil, Error("No idempotent request found with ID '%s'", thisReq.id)
+	}
+
+	kept := &idempotentResponse{}
+	if errScan := rows.Scan(&kept.fingerprint, &kept.statusCode, &kept.jsonBody, &kept.creation); errScan != nil {
+		return nil, ErrorC(errScan, "Could not read the idempotent request")
+	}
+
+	return kept, rows.Err()
+}
+
+// keeps the response given to this request, so it can be replayed; a failed request is forgotten, so that it can be
+// retried with the same key
+func (thisReq *idempotentRequest) finish(resp *response) {
+	if resp.StatusCode == idempotencySTATUSxPENDING || resp.StatusCode >= http.StatusInternalServerError {
+		if _, errDelete := thisReq.db.Exec(thisReq.db.adapter.getDeleteQuery(idempotencyTABLE, idempotencyID, 1), thisReq.id); errDelete != nil {
+			slog.Error(fmt.Sprintf("Could not forget the idempotent request '%s': %s", thisReq.id, errDelete))
+		}
+
+		return
+	}
+
+	jsonBytes, errMarshal := json.MarshalIndent(resp, "", "\t")
+	if errMarshal != nil {
+		slog.Error(fmt.Sprintf("Could not marshal the response to the idempotent request '%s': %s", thisReq.id, errMarshal))
+		return
+	}
+
+	if _, errUpdate := thisReq.db.Exec(thisReq.db.adapter.getUpdateManyQuery(idempotencyTABLE, idempotencyCOLUMNS, 1),
+		thisReq.id, thisReq.fingerprint, resp.StatusCode, string(jsonBytes), thisReq.creation); errUpdate != nil {
+		slog.Error(fmt.Sprintf("Could not keep the response to the idempotent request '%s': %s", thisReq.id, errUpdate))
+	}
+}
+
+// writing out the response kept for a previous request
+func (thisResp *idempotentResponse) replay(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set(idempotentReplayedHEADER, "true")
+	w.WriteHeader(thisResp.statusCode)
+
+	if _, errWrite := w.Write([]byte(thisResp.jsonBody.String)); errWrite != nil {
+		slog.Error(fmt.Sprintf("Could not replay the response to an idempotent request: %s", errWrite))
+	}
+}
+
+// regularly purging, in the background, the expired idempotent requests, so that their keys can be reused
+func (thisServer *server) startIdempotencyPurges() {
+	ttl := thisServer.config.commonPart().HTTP.idempotencyTTL
+
+	dbRegistry.mx.Lock()
+	defer dbRegistry.mx.Unlock()
+
+	for _, db := range core.GetSortedValues(dbRegistry.databases) {
+		if hasIdempotentEndpoints(db) {
+			go func() {
+				for ; ; time.Sleep(min(ttl, idempotencyPURGExPERIOD)) {
+					purgeIdempotentRequests(db, ttl)
+				}
+			}()
+		}
+	}
+}
+
+// forgetting the requests received more than the given TTL ago
+func purgeIdempotentRequests(db *DB, ttl time.Duration) {
+	defer RecoverError("Error while purging the expired idempotent requests of DB '%s'", db.config.DbID)
+
+	if _, errPurge := db.Exec(db.adapter.getDeleteBeforeQuery(idempotencyTABLE, idempotencyCREATION),
+		time.Now().UTC().Add(-ttl)); errPurge != nil {
+		slog.Error(fmt.Sprintf("Could not purge the expired idempotent requests of DB '%s': %s", db.config.DbID, errPurge))
+	}
+}
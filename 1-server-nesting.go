@@ -0,0 +1,67 @@
+// ------------------------------------------------------------------------------------------------
+// Here we make sure the endpoints nested under their parent's route, e.g. "/orders/:ID/lines",
+// only read or write the BOs belonging to the parent given in the path
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"errors"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/hstatus"
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+// checks that the BO targeted by the request, if any, belongs to the parent given in the path; an error comes with the
+// status to send back: 404 if there's no such BO under this parent
+func checkParentOfTarget(webCtx *webContextImpl) (hstatus.Code, error) {
+	childToParent := webCtx.getParentRelationship()
+	idProp := webCtx.ep.getIDProp()
+	if childToParent == nil || idProp == nil || childToParent.ownerSpecs().isNotPersisted() {
+		return hstatus.OK, nil
+	}
+
+	target, errLoad := dbLoadOne(webCtx.GetBloContext().GetDaoContext(), idProp, webCtx.GetTargetRefOrID(), childToParent.getName())
+	if errLoad != nil {
+		var notFoundErr *NotFoundError
+		return core.IfThenElse(errors.As(errLoad, &notFoundErr), hstatus.NotFound, hstatus.InternalServerError),
+			ErrorC(errLoad, "Could not load the targeted '%s'", childToParent.ownerSpecs().base().name)
+	}
+
+	if parentID := getClass(childToParent.ownerSpecs()).GetValueAsString(target, childToParent.getName()); parentID != webCtx.GetParentID() {
+		return hstatus.NotFound, Error("No '%s' found with '%s = %s' for '%s' %s", childToParent.ownerSpecs().base().name,
+			idProp.getName(), webCtx.GetTargetRefOrID(), childToParent.getName(), webCtx.GetParentID())
+	}
+
+	return hstatus.OK, nil
+}
+
+// checks that the given input BOs belong to the parent given in the path; the ones with no parent yet are given this one
+func checkParentOfInputs[InputType IBusinessObject](webCtx WebContext, inputs []InputType) error {
+	childToParent := webCtx.getParentRelationship()
+	if childToParent == nil || len(inputs) == 0 {
+		return nil
+	}
+
+	// the input BOs may not be children of the parent, e.g. when they are some action's parameters
+	inputSpecs := specsForName(className(utils.TypeNameOf(inputs[0], true)))
+	if inputSpecs == nil || inputSpecs.base().relationships[childToParent.getName()] != childToParent {
+		return nil
+	}
+
+	class := getClass(inputSpecs)
+	for i, input := range inputs {
+		switch parentID := class.GetValueAsString(input, childToParent.getName()); parentID {
+		case webCtx.GetParentID():
+			// all good
+		case "":
+			if errSet := class.SetValueAsString(input, childToParent.getName(), webCtx.GetParentID()); errSet != nil {
+				return ErrorC(errSet, "Could not set '%s' on input #%d", childToParent.getName(), i)
+			}
+		default:
+			return Error("Input #%d has '%s' %s, instead of %s", i, childToParent.getName(), parentID, webCtx.GetParentID())
+		}
+	}
+
+	return nil
+}
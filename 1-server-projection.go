@@ -0,0 +1,111 @@
+// ------------------------------------------------------------------------------------------------
+// Here we handle the field projections, i.e. the GET requests only asking for some of the
+// resources' properties, e.g. "/users?fields=Name,Email", to lighten both the DB queries & the responses
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const fieldsQUERYxPARAM = "fields" // the query param listing the properties to send back, separated by commas
+
+// reads the properties asked for with the "fields" query param, which must all be properties of the endpoint's resource
+func retrieveSelectedFields(req *http.Request, ep iEndpoint) (selected []string, fieldErrors []*FieldError) {
+	fieldsParam := req.URL.Query().Get(fieldsQUERYxPARAM)
+	if fieldsParam == "" || req.Method != http.MethodGet {
+		return nil, nil
+	}
+
+	resourceSpecs := specsForName(ep.getResourceClass())
+	for _, name := range strings.Split(fieldsParam, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		if resourceSpecs == nil || !isSelectable(resourceSpecs, name) {
+			fieldErrors = append(fieldErrors, &FieldError{Field: name, Rule: ValidationRuleSELECTABLE})
+		} else {
+			selected = append(selected, name)
+		}
+	}
+
+	return
+}
+
+// only the ID, the fields & the relationships of a class can be selected
+func isSelectable(boSpecs IBusinessObjectSpecs, name string) bool {
+	return name == "ID" || boSpecs.base().fields[name] != nil || boSpecs.base().relationships[name] != nil
+}
+
+// keeps only the given properties - and the ID - in the JSON form of the given BO, or list of BOs
+func projectOutput(output any, propertyNames []string) (any, error) {
+	jsonBytes, errMarshal := json.Marshal(output)
+	if errMarshal != nil {
+		return nil, ErrorC(errMarshal, "Could not marshal the output to project")
+	}
+
+	kept := map[string]bool{"ID": true}
+	for _, name := range propertyNames {
+		kept[name] = true
+	}
+
+	project := func(object map[string]json.RawMessage) map[string]json.RawMessage {
+		for key := range object {
+			if !kept[key] {
+				delete(object, key)
+			}
+		}
+		return object
+	}
+
+	switch {
+	case len(jsonBytes) > 0 && jsonBytes[0] == '[':
+		objects := []map[string]json.RawMessage{}
+		if errUnmarshal := json.Unmarshal(jsonBytes, &objects); errUnmarshal != nil {
+			return nil, ErrorC(errUnmarshal, "Could not unmarshal the list to project")
+		}
+		for _, object := range objects {
+			project(object)
+		}
+		return objects, nil
+
+	case len(jsonBytes) > 0 && jsonBytes[0] == '{':
+		object := map[string]json.RawMessage{}
+		if errUnmarshal := json.Unmarshal(jsonBytes, &object); errUnmarshal != nil {
+			return nil, ErrorC(errUnmarshal, "Could not unmarshal the object to project")
+		}
+		return project(object), nil
+	}
+
+	// null, or not an object
+	return output, nil
+}
+
+// restricting the response's content to the properties asked for, or else defined for the endpoint's loading type
+func projectResponse(webCtx *webContextImpl, req *http.Request, resp *response) error {
+	if req.Method != http.MethodGet || resp.statusObj.Val() >= http.StatusMultipleChoices {
+		return nil
+	}
+
+	resourceSpecs := specsForName(webCtx.ep.getResourceClass())
+	if resourceSpecs == nil {
+		return nil
+	}
+
+	projection := getProjection(resourceSpecs, webCtx.ep.getLoadingType(), webCtx.selectedFields)
+	if len(projection) == 0 {
+		return nil
+	}
+
+	var errProject error
+	if resp.Object, errProject = projectOutput(resp.Object, projection); errProject != nil {
+		return errProject
+	}
+
+	resp.ObjectList, errProject = projectOutput(resp.ObjectList, projection)
+
+	return errProject
+}
@@ -0,0 +1,213 @@
+// ------------------------------------------------------------------------------------------------
+// Here we handle the versions of the API: each version is served under its own prefix, e.g.
+// "/api/v2/...", and the unprefixed paths serve the version asked for in a request header
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/hstatus"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	apiVersionHEADER = "Api-Version" // the header a client can use to choose a version, when calling an unprefixed path
+	apiVersionPREFIX = "/v"          // e.g. "/v2", between the API path and the endpoint's path
+)
+
+// the latest version of the API, i.e. the highest version declared on the endpoints; 0 if none is versioned
+func getLatestAPIVersion() (latest int) {
+	for _, ep := range restRegistry.endpoints {
+		minVersion, maxVersion := ep.getVersionRange()
+		latest = max(latest, minVersion, maxVersion)
+	}
+
+	return
+}
+
+// the versions of the API in which the given endpoint is served; none for an unversioned endpoint
+func getServedVersions(ep iEndpoint, latest int) (versions []int) {
+	minVersion, maxVersion := ep.getVersionRange()
+	if minVersion == 0 {
+		return nil
+	}
+
+	for version := minVersion; version <= core.IfThenElse(maxVersion == 0, latest, maxVersion); version++ {
+		versions = append(versions, version)
+	}
+
+	return
+}
+
+// the prefix of the path to use for calling the latest version of the given endpoint, e.g. "/v2"; empty for an unversioned endpoint
+func getLatestVersionPrefix(ep iEndpoint) string {
+	if versions := getServedVersions(ep, getLatestAPIVersion()); len(versions) > 0 {
+		return apiVersionPREFIX + strconv.Itoa(versions[len(versions)-1])
+	}
+
+	return ""
+}
+
+// all the endpoints sharing the same method & path, whatever their version
+type apiRoute struct {
+	method      string
+	fullPath    string
+	unversioned iEndpoint         // the endpoint served in all the versions, if any
+	byVersion   map[int]iEndpoint // the endpoint to use for each version of the API
+}
+
+// the endpoint to serve for the given version of the API, if any
+func (thisRoute *apiRoute) endpointFor(version int) iEndpoint {
+	if ep := thisRoute.byVersion[version]; ep != nil {
+		return ep
+	}
+
+	return thisRoute.unversioned
+}
+
+// the endpoint served by default on the unprefixed path: the unversioned one, or else the one of the highest version
+func (thisRoute *apiRoute) defaultEndpoint() iEndpoint {
+	if thisRoute.unversioned != nil {
+		return thisRoute.unversioned
+	}
+
+	versions := core.GetSortedKeys(thisRoute.byVersion)
+
+	return thisRoute.byVersion[versions[len(versions)-1]]
+}
+
+// groups the registered endpoints by method & path, checking that the versions of a path do not overlap
+func getAPIRoutes(latest int) (routes []*apiRoute) {
+	routesByKey := map[string]*apiRoute{}
+	for _, ep := range restRegistry.endpoints {
+		key := ep.getMethod() + " " + ep.getFullPath()
+		route := routesByKey[key]
+		if route == nil {
+			route = &apiRoute{method: ep.getMethod(), fullPath: ep.getFullPath(), byVersion: map[int]iEndpoint{}}
+			routesByKey[key] = route
+			routes = append(routes, route)
+		}
+
+		versions := getServedVersions(ep, latest)
+		if len(versions) == 0 {
+			if route.unversioned != nil {
+				core.PanicMsg("Endpoint %s declared twice without any version", key)
+			}
+			route.unversioned = ep
+		}
+
+		for _, version := range versions {
+			if route.byVersion[version] != nil {
+				core.PanicMsg("Endpoint %s declared twice for version %d", key, version)
+			}
+			route.byVersion[version] = ep
+		}
+	}
+
+	return
+}
+
+// mounting all the endpoints, for all the versions of the API
+func (thisServer *server) initAPIRoutes(apiPath string) {
+	latest := getLatestAPIVersion()
+
+	for _, route := range getAPIRoutes(latest) {
+		// each version has its own prefix - the unversioned endpoints being served in all of them
+		for version := 1; version <= latest; version++ {
+			if ep := route.endpointFor(version); ep != nil {
+				thisServer.initAPIRoute(ep, apiPath+apiVersionPREFIX+strconv.Itoa(version)+route.fullPath, thisServer.handleFor(ep))
+			}
+		}
+
+		// the unprefixed path
+		if len(route.byVersion) == 0 {
+			thisServer.initAPIRoute(route.unversioned, apiPath+route.fullPath, thisServer.handleFor(route.unversioned))
+		} else {
+			thisServer.initAPIRoute(route.defaultEndpoint(), apiPath+route.fullPath, thisServer.handleForVersions(route))
+		}
+	}
+}
+
+func (thisServer *server) initAPIRoute(ep iEndpoint, routePath string, handle httprouter.Handle) {
+	// the generic handlers cannot write into the views & tables of the read-only classes
+	if ep.isGenericWrite() && specsForName(ep.getResourceClass()).isReadOnly() {
+		core.PanicMsg("Class '%s' is read-only, so it cannot be written through endpoint %s %s",
+			ep.getResourceClass(), ep.getMethod(), routePath)
+	}
+
+	// the generic endpoints are typed with their resource's struct, which the BOs of its subclasses cannot be cast into
+	if resourceSpecs := specsForName(ep.getResourceClass()); resourceSpecs != nil && resourceSpecs.base().loadsSubclassInstances() {
+		core.PanicMsg("Class '%s' has subclasses sharing its persistence, so its BOs cannot be typed as '%s' by endpoint %s %s",
+			ep.getResourceClass(), ep.getResourceClass(), ep.getMethod(), routePath)
+	}
+
+	// the idempotent requests are kept in the DB of the endpoint's resources
+	if ep.isIdempotent() && getIdempotencyDB(ep) == nil {
+		core.PanicMsg("Class '%s' is not persisted, so endpoint %s %s cannot be idempotent",
+			ep.getResourceClass(), ep.getMethod(), routePath)
+	}
+
+	slog.Info(fmt.Sprintf("Serving: %s %s%s", ep.getMethod(), routePath, describeDeprecation(ep)))
+	thisServer.router.Handle(ep.getMethod(), routePath, handle)
+}
+
+// serving the endpoint corresponding to the version asked for in the request header, or else the unversioned
+// endpoint, or else the latest version of the endpoint
+func (thisServer *server) handleForVersions(route *apiRoute) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		ep := route.defaultEndpoint()
+
+		if versionHeader := req.Header.Get(apiVersionHEADER); versionHeader != "" {
+			version, errConv := strconv.Atoi(strings.TrimPrefix(strings.ToLower(versionHeader), "v"))
+			if errConv != nil {
+				(&httpRequestContext{server: thisServer}).write(&response{
+					statusObj: hstatus.BadRequest,
+					Message:   fmt.Sprintf("Invalid %s header: '%s'", apiVersionHEADER, versionHeader),
+				}, w)
+
+				return
+			}
+
+			if ep = route.endpointFor(version); ep == nil {
+				(&httpRequestContext{server: thisServer}).write(&response{
+					statusObj: hstatus.NotFound,
+					Message:   fmt.Sprintf("No version %d for %s %s", version, route.method, route.fullPath),
+				}, w)
+
+				return
+			}
+		}
+
+		thisServer.ServeEndpoint(ep, w, req, params)
+	}
+}
+
+// warning the clients of a deprecated endpoint, through the "Deprecation" & "Sunset" headers
+func setDeprecationHeaders(ep iEndpoint, w http.ResponseWriter) {
+	if ep.isDeprecated() {
+		w.Header().Set("Deprecation", "true")
+		if !ep.getSunset().IsZero() {
+			w.Header().Set("Sunset", ep.getSunset().UTC().Format(http.TimeFormat))
+		}
+	}
+}
+
+// e.g. " (deprecated, sunset on 2025-06-30)", to be appended to an endpoint's description
+func describeDeprecation(ep iEndpoint) string {
+	if !ep.isDeprecated() {
+		return ""
+	}
+
+	if ep.getSunset().IsZero() {
+		return " (deprecated)"
+	}
+
+	return fmt.Sprintf(" (deprecated, sunset on %s)", ep.getSunset().Format(time.DateOnly))
+}
@@ -64,6 +64,13 @@ func (thisServer *server) runCodeGen(srcdir string, level codeGenLevel, webdir,
 		thisServer.generateAllClientAppModels(webdir, regen, true)
 		thisServer.generateAllClientAppModels(nativedir, regen, false)
 
+		// the typed API clients, using these models' types
+		thisServer.generateClientAppAPIClient(webdir, true)
+		thisServer.generateClientAppAPIClient(nativedir, false)
+
+		// the contract of our API, for the partners
+		thisServer.generateOpenAPIDocument(srcdir)
+
 		// saving the dirty state
 		core.WriteToFile(fmt.Sprintf("%t", codeChanged), bindir, dirtyFILENAME)
 
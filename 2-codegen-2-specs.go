@@ -186,7 +186,7 @@ func buildPropDecl(class IClass, context *specsGenerationContext) (result string
 		field := bObjType.Field(fieldNum)
 
 		// detecting its type and multiplicity
-		typeFamily, multiple := utils.GetTypeFamily(field, typeIxBUSINESSxOBJECT, typeIxENUM)
+		typeFamily, multiple := utils.GetTypeFamily(field, typeIxBUSINESSxOBJECT, typeIxENUM, typeATTACHMENTxPTR)
 
 		// adding to the context, and the class file content
 		if typeFamily != utils.TypeFamilyUNKNOWN {
@@ -204,7 +204,7 @@ func buildPropDecl(class IClass, context *specsGenerationContext) (result string
 				}
 				targetTypes = getImplementionsOfInterface(interfaceType)
 			} else if typeFamily == utils.TypeFamilyENUM { // or enums.
-				targetType = field.Type().String()
+				targetType = core.IfThenElse(multiple, field.Type().Elem(), field.Type()).String()
 			}
 
 			// keeping track of the property's characteristics - this will be of use in the init function of the Specs object
@@ -236,8 +236,22 @@ func getFieldForType(typeFamily utils.TypeFamily) string {
 		return "RealField"
 	case utils.TypeFamilyDOUBLE:
 		return "DoubleField"
+	case utils.TypeFamilyDECIMAL:
+		return "DecimalField"
 	case utils.TypeFamilyDATE:
 		return "DateField"
+	case utils.TypeFamilyDATExONLY:
+		return "DateOnlyField"
+	case utils.TypeFamilyTIMExOFxDAY:
+		return "TimeOfDayField"
+	case utils.TypeFamilyDURATION:
+		return "DurationField"
+	case utils.TypeFamilyJSON:
+		return "JSONField"
+	case utils.TypeFamilyBINARY:
+		return "BinaryField"
+	case utils.TypeFamilyATTACHMENT:
+		return "AttachmentField"
 	case utils.TypeFamilyENUM:
 		return "EnumField"
 	default:
@@ -0,0 +1,326 @@
+// ------------------------------------------------------------------------------------------------
+// Here is the code used for generating the typed API client used by the web app, or the native app:
+// 1 function per endpoint called from the app, along with the types of the BOs sent & received
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+const apiClientFILExNAME = "apiClient.ts"
+
+// the part of the client that does not depend on the endpoints
+const apiClientCOMMONxPART = `// ------------------------------------------------------------------------------------------------
+// Common part
+// ------------------------------------------------------------------------------------------------
+
+// the envelope of all the JSON responses sent by the API
+export interface ApiResponse<T> {
+    Object?: T;
+    ObjectList?: T[];
+    StatusCode: number;
+    Status: string;
+    Message: string;
+    Details?: unknown;
+}
+
+// the error thrown when the API does not answer with a 2XX status
+export class ApiError extends Error {
+    constructor(public readonly response: ApiResponse<unknown>) {
+        super(response.Message);
+    }
+}
+
+// the metadata of an attached file
+export interface Attachment {
+    Name: string;
+    Size: number;
+    ContentType: string;
+    Checksum: string;
+}
+
+// what the API paths are relative to, e.g. "https://api.example.com"
+let baseURL = '';
+
+export function setBaseURL(url: string) {
+    baseURL = url;
+}
+
+async function send(method: string, path: string, query?: object, body?: BodyInit, contentType?: string, headers?: Record<string, string>): Promise<Response> {
+    let url = baseURL + path;
+    if (query) {
+        const params = new URLSearchParams();
+        for (const [key, value] of Object.entries(query)) {
+            if (value !== undefined && value !== null) {
+                params.append(key, String(value));
+            }
+        }
+        if (params.toString() !== '') {
+            url += '?' + params.toString();
+        }
+    }
+
+    return fetch(url, { method, body, headers: { ...(contentType ? { 'Content-Type': contentType } : {}), ...headers } });
+}
+
+async function call<T>(method: string, path: string, query?: object, body?: BodyInit, contentType?: string, headers?: Record<string, string>): Promise<ApiResponse<T>> {
+    const resp = await send(method, path, query, body, contentType, headers);
+    const apiResp = (await resp.json()) as ApiResponse<T>;
+    if (!resp.ok) {
+        throw new ApiError(apiResp);
+    }
+
+    return apiResp;
+}
+
+async function download(path: string): Promise<Blob> {
+    const resp = await send('GET', path);
+    if (!resp.ok) {
+        throw new ApiError((await resp.json()) as ApiResponse<unknown>);
+    }
+
+    return resp.blob();
+}
+`
+
+// generates the API client in the given app, for all the endpoints called from this app
+func (thisServer *server) generateClientAppAPIClient(destdir string, isWebapp bool) {
+	endpoints := []iEndpoint{}
+	for _, ep := range restRegistry.endpoints {
+		if (isWebapp && ep.isCalledFromWebApp()) || (!isWebapp && ep.isCalledFromNativeApp()) {
+			endpoints = append(endpoints, ep)
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return
+	}
+
+	// the classes sent or received through these endpoints
+	classes := map[className]bool{}
+	for _, ep := range endpoints {
+		classes[ep.getResourceClass()] = true
+		if ep.getInputOrParamsClass() != "" {
+			classes[ep.getInputOrParamsClass()] = true
+		}
+	}
+
+	// the types of these classes, and the enums they need
+	enumVars := map[string]bool{}
+	types := []string{}
+	for _, clsName := range core.GetSortedKeys(classes) {
+		types = append(types, getClientTypeDeclaration(specsForName(clsName), enumVars))
+	}
+
+	// 1 function per endpoint
+	apiPath := thisServer.config.commonPart().HTTP.ApiPath
+	functions := []string{}
+	usedNames := map[string]int{}
+	for _, ep := range endpoints {
+		functions = append(functions, getClientFunction(ep, apiPath, usedNames))
+	}
+
+	// putting everything together
+	content := "// Generated by Aldev, do not edit!" + newline
+	for _, enumVar := range core.GetSortedKeys(enumVars) {
+		content += fmt.Sprintf("import * as %s from './%s';", enumVar, enumVar) + newline
+	}
+	content += newline + apiClientCOMMONxPART + newline
+	content += getClientBanner("Types") + strings.Join(types, newline) + newline
+	content += getClientBanner("Endpoints") + strings.Join(functions, newline)
+
+	filepath := path.Join(destdir, modelsDIRPATH, apiClientFILExNAME)
+	core.WriteToFile(content, filepath)
+	slog.Info(fmt.Sprintf("(Re-)generated file %s", filepath))
+}
+
+func getClientBanner(title string) string {
+	line := "// " + strings.Repeat("-", 96) + newline
+
+	return line + "// " + title + newline + line + newline
+}
+
+// the TS interface describing the BOs of the given class
+func getClientTypeDeclaration(boSpecs IBusinessObjectSpecs, enumVars map[string]bool) string {
+	codeCtx := &codeContext{bObjType: utils.TypeOf(getClass(boSpecs).NewObject(), true)}
+
+	decl := fmt.Sprintf("export interface %s {", boSpecs.base().name) + newline
+	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
+		if field.getName() == "ID" {
+			decl += fmt.Sprintf("    ID?: %s;", core.IfThenElse(boSpecs.getIDStrategy().isUUID(), "string", "number")) + newline
+		} else if tsType := getClientFieldType(codeCtx, field, enumVars); tsType != "" {
+			decl += fmt.Sprintf("    %s: %s;", field.getName(), tsType) + newline
+		}
+	}
+
+	return decl + "}" + newline
+}
+
+// the TS type of the values of the given field, as sent & received in JSON
+func getClientFieldType(codeCtx *codeContext, field IField, enumVars map[string]bool) (tsType string) {
+	switch field.getTypeFamily() {
+	case utils.TypeFamilyENUM:
+		enumType := codeCtx.getEnumType(field)
+		enumVar := "_" + core.PascalToCamel(enumType)
+		enumVars[enumVar] = true
+		tsType = enumVar + "." + enumType
+	case utils.TypeFamilySTRING, utils.TypeFamilyDECIMAL, utils.TypeFamilyBINARY, utils.TypeFamilyTIMExOFxDAY:
+		tsType = "string"
+	case utils.TypeFamilyINT, utils.TypeFamilyBIGINT, utils.TypeFamilyREAL, utils.TypeFamilyDOUBLE, utils.TypeFamilyDURATION:
+		tsType = "number"
+	case utils.TypeFamilyBOOL:
+		tsType = "boolean"
+	case utils.TypeFamilyDATE, utils.TypeFamilyDATExONLY:
+		tsType = "string | null"
+	case utils.TypeFamilyJSON:
+		tsType = "unknown"
+	case utils.TypeFamilyATTACHMENT:
+		tsType = "Attachment | null"
+	default:
+		return ""
+	}
+
+	if field.isMultiple() {
+		return core.IfThenElse(strings.Contains(tsType, " "), "("+tsType+")[]", tsType+"[]")
+	}
+
+	return tsType
+}
+
+// the line calling the API, with the given args - query, body, content type & headers - the trailing undefined ones
+// being left out
+func getClientCall(resourceType string, method string, urlPath string, args ...string) string {
+	for len(args) > 0 && args[len(args)-1] == "undefined" {
+		args = args[:len(args)-1]
+	}
+
+	return fmt.Sprintf("    const resp = await call<%s>(%s);", resourceType, strings.Join(append([]string{"'" + method + "'", urlPath}, args...), ", "))
+}
+
+// the TS function calling the given endpoint
+func getClientFunction(ep iEndpoint, apiPath string, usedNames map[string]int) string {
+	resourceType := string(ep.getResourceClass())
+	outputType := core.IfThenElse(ep.isMultipleOutput(), resourceType+"[]", resourceType)
+
+	// the function's parameters, and the URL path
+	params := []string{}
+	urlPath := apiPath + getLatestVersionPrefix(ep) + ep.getFullPath()
+	if childToParent := ep.getParentRelationship(); childToParent != nil {
+		// the parent's ID comes 1st in the path, and is named after the parent's class in the function, e.g. "orderID"
+		parentIDArg := core.PascalToCamel(string(childToParent.targets[0].base().name)) + ep.getParentIDParam()
+		params = append(params, parentIDArg+": string | number")
+		urlPath = strings.Replace(urlPath, ":"+ep.getParentIDParam(), "${encodeURIComponent(String("+parentIDArg+"))}", 1)
+	}
+	if idParam := ep.getIDParam(); idParam != "" {
+		params = append(params, idParam+": string | number")
+		urlPath = strings.Replace(urlPath, ":"+idParam, "${encodeURIComponent(String("+idParam+"))}", 1)
+	}
+	urlPath = "`" + urlPath + "`"
+
+	// the call itself, which depends on the endpoint's type
+	var body string
+	isGet := ep.getMethod() == http.MethodGet && !ep.isFileDownload()
+	query, reqBody, contentType, headers := "undefined", "undefined", "undefined", "undefined"
+	if isGet {
+		query = fmt.Sprintf("{ %s: fields?.join(',') }", fieldsQUERYxPARAM)
+	}
+
+	switch {
+	case ep.isFileDownload():
+		outputType = "Blob"
+		body = fmt.Sprintf("    return download(%s);", urlPath)
+
+	case ep.isFileUpload():
+		params = append(params, "file: Blob", "fileName?: string")
+		body = "    const form = new FormData();" + newline +
+			fmt.Sprintf("    form.append('%s', file, fileName);", uploadFORMxKEY) + newline
+		reqBody = "form"
+
+	case ep.isPatch():
+		params = append(params, fmt.Sprintf("patch: Partial<%s>", resourceType))
+		reqBody, contentType = "JSON.stringify(patch)", "'application/json'"
+
+	case ep.hasBodyOrParamsInput() && ep.isBodyInputRequired():
+		inputType := string(ep.getInputOrParamsClass())
+		params = append(params, "input: "+core.IfThenElse(ep.isMultipleInput(), inputType+"[]", inputType))
+		reqBody, contentType = "JSON.stringify(input)", "'application/json'"
+
+	case ep.hasBodyOrParamsInput():
+		params = append(params, fmt.Sprintf("params: Partial<%s>", ep.getInputOrParamsClass()))
+		query = core.IfThenElse(isGet, fmt.Sprintf("{ ...params, %s: fields?.join(',') }", fieldsQUERYxPARAM), "params")
+	}
+
+	// a GET request can ask for only some of the resource's properties
+	if isGet {
+		params = append(params, fmt.Sprintf("fields?: (keyof %s)[]", resourceType))
+	}
+
+	// a retried idempotent request must be sent with the same key as the 1st time
+	if ep.isIdempotent() {
+		params = append(params, "idempotencyKey?: string")
+		headers = fmt.Sprintf("idempotencyKey ? { '%s': idempotencyKey } : undefined", idempotencyKeyHEADER)
+	}
+
+	if !ep.isFileDownload() {
+		body += getClientCall(resourceType, ep.getMethod(), urlPath, query, reqBody, contentType, headers)
+	}
+
+	// unwrapping the response envelope
+	if !ep.isFileDownload() {
+		body += newline + core.IfThenElse(ep.isMultipleOutput(), "    return resp.ObjectList ?? [];", "    return resp.Object as "+resourceType+";")
+	}
+
+	function := ""
+	if label := ep.getLabel(); label != "" {
+		function += "// " + label + newline
+	}
+	if ep.isDeprecated() {
+		function += "/** @deprecated" + core.IfThenElse(ep.getSunset().IsZero(), "", " sunset on "+ep.getSunset().Format(time.DateOnly)) + " */" + newline
+	}
+	function += fmt.Sprintf("export async function %s(%s): Promise<%s> {", getClientFunctionName(ep, usedNames), strings.Join(params, ", "), outputType) +
+		newline + body + newline + "}" + newline
+
+	return function
+}
+
+// e.g. "getTranslationListByLang" for: GET /translations/:Lang, returning several translations
+func getClientFunctionName(ep iEndpoint, usedNames map[string]int) string {
+	name := strings.ToLower(ep.getMethod()) + string(ep.getResourceClass())
+	if ep.isMultipleOutput() && ep.getMethod() == http.MethodGet {
+		name += "List"
+	}
+
+	// the action path, if any, e.g. "bulk", or the targeted part of the BO, e.g. "photo" for an attachment
+	for _, part := range strings.FieldsFunc(ep.getActionPath()+"/"+ep.getSubPath(), func(r rune) bool { return r == '/' || r == '-' || r == '_' }) {
+		name += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	// the parent, for a nested endpoint
+	if childToParent := ep.getParentRelationship(); childToParent != nil {
+		name += "Of" + string(childToParent.targets[0].base().name)
+	}
+
+	if ep.getIDProp() != nil {
+		name += "By" + ep.getIDProp().getName()
+	}
+
+	// the versioned endpoints are called in their latest version, e.g. "/v2"
+	name += strings.ToUpper(strings.TrimPrefix(getLatestVersionPrefix(ep), "/"))
+
+	// making sure each function has a unique name
+	usedNames[name]++
+	if usedNames[name] > 1 {
+		name += fmt.Sprintf("%d", usedNames[name])
+	}
+
+	return name
+}
@@ -0,0 +1,84 @@
+package goald
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+func TestGetClientFunctionName(t *testing.T) {
+	langField := &StringField{field: newField(nil, "Lang", false, utils.TypeFamilySTRING)}
+	idField := &BigIntField{numericField: numericField{field: newField(nil, "ID", false, utils.TypeFamilyBIGINT)}}
+
+	orderSpecs := NewBusinessObjectSpecs()
+	orderSpecs.base().name = "Order"
+	lineToOrder := &Relationship{targets: []IBusinessObjectSpecs{orderSpecs}}
+
+	tests := []struct {
+		name     string
+		ep       *endpoint[*BusinessObject]
+		expected string
+	}{
+		{"get one",
+			&endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "Translation"},
+			"getTranslation"},
+		{"get many",
+			&endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "Translation", multipleOutput: true},
+			"getTranslationList"},
+		{"get many, targeted",
+			&endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "Translation", multipleOutput: true, idProp: langField},
+			"getTranslationListByLang"},
+		{"post many, no list suffix",
+			&endpoint[*BusinessObject]{method: http.MethodPost, resourceClass: "Translation", multipleOutput: true},
+			"postTranslation"},
+		{"put, targeted",
+			&endpoint[*BusinessObject]{method: http.MethodPut, resourceClass: "Translation", idProp: idField},
+			"putTranslationByID"},
+		{"action path",
+			&endpoint[*BusinessObject]{method: http.MethodPost, resourceClass: "Translation", actionPath: "bulk"},
+			"postTranslationBulk"},
+		{"action path with separators",
+			&endpoint[*BusinessObject]{method: http.MethodPost, resourceClass: "User", actionPath: "profile/reset-photo_now", idProp: idField},
+			"postUserProfileResetPhotoNowByID"},
+		{"attachment",
+			&endpoint[*BusinessObject]{method: http.MethodPost, resourceClass: "User", idProp: idField, subPath: "profile-photo"},
+			"postUserProfilePhotoByID"},
+		{"nested",
+			&endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "OrderLine", multipleOutput: true, parentRelationship: lineToOrder},
+			"getOrderLineListOfOrder"},
+		{"nested, targeted",
+			&endpoint[*BusinessObject]{method: http.MethodDelete, resourceClass: "OrderLine", parentRelationship: lineToOrder, idProp: idField},
+			"deleteOrderLineOfOrderByID"},
+		{"versioned",
+			&endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "Translation", minVersion: 1, maxVersion: 2},
+			"getTranslationV2"},
+		{"versioned, targeted",
+			&endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "Translation", idProp: langField, minVersion: 3, maxVersion: 3},
+			"getTranslationByLangV3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getClientFunctionName(tt.ep, map[string]int{}); got != tt.expected {
+				t.Errorf("getClientFunctionName() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetClientFunctionNameUniqueness(t *testing.T) {
+	usedNames := map[string]int{}
+	ep := &endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "Translation"}
+	other := &endpoint[*BusinessObject]{method: http.MethodGet, resourceClass: "User"}
+
+	for i, expected := range []string{"getTranslation", "getTranslation2", "getTranslation3"} {
+		if got := getClientFunctionName(ep, usedNames); got != expected {
+			t.Errorf("call #%d: getClientFunctionName() = %s, expected %s", i+1, got, expected)
+		}
+	}
+
+	if got := getClientFunctionName(other, usedNames); got != "getUser" {
+		t.Errorf("getClientFunctionName() = %s, expected getUser", got)
+	}
+}
@@ -32,9 +32,29 @@ $$getcases$$
 func (thisClass *$$Upper$$Class) SetValueAsString(bo goald.IBusinessObject, propertyName string, valueAsString string) error {
 	switch propertyName {
 $$setcases$$
+	default:
+		return goald.Error("Unknown property: %T.%s", bo, propertyName)
 	}
 
-	return goald.Error("Unknown property: %T.%s", bo, propertyName)
+	return nil
+}
+
+// getting the number of BOs a multiple relationship points to, without using reflection
+func (thisClass *$$Upper$$Class) GetNumberOf(bo goald.IBusinessObject, relationshipName string) int {
+	switch relationshipName {
+$$countcases$$
+	default:
+		return 0
+	}
+}
+
+// setting the BOs a relationship points to, once they've been loaded, without using reflection
+func (thisClass *$$Upper$$Class) SetRelated(bo goald.IBusinessObject, relationshipName string, related []goald.IBusinessObject) error {
+	switch relationshipName {
+$$relatedcases$$
+	default:
+		return goald.Error("Unknown relationship: %T.%s", bo, relationshipName)
+	}
 }
 `
 
@@ -129,77 +149,212 @@ func generateObjectValueMappersForBO(class IClass, filepath string) {
 	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
 		// adding to the context, and the class file content
 		if typeFamily := field.getTypeFamily(); typeFamily != utils.TypeFamilyUNKNOWN && typeFamily != utils.TypeFamilyRELATIONSHIPxMONOM {
-			// not handling multiple properties for now
-			if fieldName := field.getName(); !field.isMultiple() {
-				// is the field type a type alias, or a built-in type?
-				fieldTypeAlias := getNonBuiltInFieldType(bObjectType, fieldName, importsMap)
-
-				// case init
-				getCase := fmt.Sprintf("\tcase \"%s\":", fieldName)
-				setCase := getCase
-
-				// this is going to come up a lot
-				fieldID := fmt.Sprintf("(*%s.%s).%s", shortPkg, className, fieldName)
-
-				switch typeFamily {
-				case utils.TypeFamilyBOOL:
-					getBit, setBit, end := getBits(fieldTypeAlias, "bool")
-					getCase += newline + fmt.Sprintf("\t\treturn core.BoolToString(%sbo.%s%s)", getBit, fieldID, end)
-					importUtils = true
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToBool(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
-
-				case utils.TypeFamilySTRING:
-					getBit, setBit, end := getBits(fieldTypeAlias, "string")
-					getCase += newline + fmt.Sprintf("\t\treturn %sbo.%s%s", getBit, fieldID, end)
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %svalueAsString%s", fieldID, setBit, end)
-
-				case utils.TypeFamilyINT:
-					getBit, setBit, end := getBits(fieldTypeAlias, "int")
-					getCase += newline + fmt.Sprintf("\t\treturn core.IntToString(%sbo.%s%s)", getBit, fieldID, end)
-					importUtils = true
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToInt(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
-
-				case utils.TypeFamilyBIGINT:
-					getBit, setBit, end := getBits(fieldTypeAlias, "int64")
-					getCase += newline + fmt.Sprintf("\t\treturn core.Int64ToString(%sbo.%s%s)", getBit, fieldID, end)
-					importUtils = true
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToInt64(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
-
-				case utils.TypeFamilyREAL:
-					getBit, setBit, end := getBits(fieldTypeAlias, "float32")
-					getCase += newline + fmt.Sprintf("\t\treturn core.Float32ToString(%sbo.%s%s)", getBit, fieldID, end)
-					importUtils = true
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToFloat32(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
-
-				case utils.TypeFamilyDOUBLE:
-					getBit, setBit, end := getBits(fieldTypeAlias, "float64")
-					getCase += newline + fmt.Sprintf("\t\treturn core.Float64ToString(%sbo.%s%s)", getBit, fieldID, end)
-					importUtils = true
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToFloat64(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
-
-				case utils.TypeFamilyDATE:
-					getCase += newline + fmt.Sprintf("\t\treturn core.DateToString(bo.%s)", fieldID)
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = core.StringToDate(valueAsString, \"%s\")", fieldID, fieldID)
-
-				case utils.TypeFamilyENUM:
-					getCase += newline + fmt.Sprintf("\t\treturn core.IntToString(bo.%s.Val())", fieldID)
-					importUtils = true
-					setCase += newline + fmt.Sprintf("\t\tbo.%s = %s(core.StringToInt(valueAsString, \"%s\"))", fieldID, fieldTypeAlias, fieldID)
-
-					setCase += newline + fmt.Sprintf("\t\tcore.PanicMsgIf(bo.%s.String() == \"\", \"Could not set '%s' to %%s since it's not a listed value\", valueAsString)",
-						fieldID, fieldID)
-				}
+			fieldName := field.getName()
+
+			// is the field type a type alias, or a built-in type?
+			fieldTypeAlias := getNonBuiltInFieldType(bObjectType, fieldName, importsMap)
+
+			// case init
+			getCase := fmt.Sprintf("\tcase \"%s\":", fieldName)
+			setCase := getCase
+
+			// this is going to come up a lot
+			fieldID := fmt.Sprintf("(*%s.%s).%s", shortPkg, className, fieldName)
+
+			switch {
+			case fieldName == "ID":
+				// the ID can be an integer or a UUID, and is handled as a string anyway
+				getCase += newline + fmt.Sprintf("\t\treturn string(bo.%s)", fieldID)
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = goald.BObjID(valueAsString)", fieldID)
 
-				// appending the case
-				getCases = append(getCases, getCase)
-				setCases = append(setCases, setCase)
+			case field.isMultiple() && typeFamily != utils.TypeFamilyENUM:
+				// the multiple primitive values are handled as JSON arrays
+				fieldType := bObjectType.FieldByName(fieldName).Type()
+				if itemPkg := fieldType.Elem().PkgPath(); itemPkg != "" {
+					importsMap[itemPkg] = true
+				}
+				getCase += getJSONGetCase(fieldID)
+				setCase += getJSONSetCase(fieldID, fieldType.String())
+				importsMap["encoding/json"] = true
+
+			case typeFamily == utils.TypeFamilyBOOL:
+				getBit, setBit, end := getBits(fieldTypeAlias, "bool")
+				getCase += newline + fmt.Sprintf("\t\treturn core.BoolToString(%sbo.%s%s)", getBit, fieldID, end)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToBool(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
+
+			case typeFamily == utils.TypeFamilySTRING:
+				getBit, setBit, end := getBits(fieldTypeAlias, "string")
+				getCase += newline + fmt.Sprintf("\t\treturn %sbo.%s%s", getBit, fieldID, end)
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %svalueAsString%s", fieldID, setBit, end)
+
+			case typeFamily == utils.TypeFamilyINT:
+				getBit, setBit, end := getBits(fieldTypeAlias, "int")
+				getCase += newline + fmt.Sprintf("\t\treturn core.IntToString(%sbo.%s%s)", getBit, fieldID, end)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToInt(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
+
+			case typeFamily == utils.TypeFamilyBIGINT:
+				getBit, setBit, end := getBits(fieldTypeAlias, "int64")
+				getCase += newline + fmt.Sprintf("\t\treturn core.Int64ToString(%sbo.%s%s)", getBit, fieldID, end)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToInt64(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
+
+			case typeFamily == utils.TypeFamilyREAL:
+				getBit, setBit, end := getBits(fieldTypeAlias, "float32")
+				getCase += newline + fmt.Sprintf("\t\treturn core.Float32ToString(%sbo.%s%s)", getBit, fieldID, end)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToFloat32(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
+
+			case typeFamily == utils.TypeFamilyDOUBLE:
+				getBit, setBit, end := getBits(fieldTypeAlias, "float64")
+				getCase += newline + fmt.Sprintf("\t\treturn core.Float64ToString(%sbo.%s%s)", getBit, fieldID, end)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToFloat64(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
+
+			case typeFamily == utils.TypeFamilyDECIMAL:
+				getCase += newline + fmt.Sprintf("\t\treturn bo.%s.String()", fieldID)
+				setCase += newline + "\t\tif valueAsString == \"\" {" +
+					newline + fmt.Sprintf("\t\t\tbo.%s = decimal.Zero", fieldID) +
+					newline + "\t\t\treturn nil" +
+					newline + "\t\t}"
+				setCase += newline + "\t\tvalue, errParse := decimal.NewFromString(valueAsString)" +
+					newline + "\t\tif errParse != nil {" +
+					newline + fmt.Sprintf("\t\t\treturn goald.ErrorC(errParse, \"Could not set '%s' to %%s\", valueAsString)", fieldID) +
+					newline + "\t\t}"
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = value", fieldID)
+				importsMap["github.com/shopspring/decimal"] = true
+
+			case typeFamily == utils.TypeFamilyDATE:
+				getCase += newline + fmt.Sprintf("\t\tif bo.%s == nil {", fieldID) +
+					newline + "\t\t\treturn \"\"" +
+					newline + "\t\t}"
+				getCase += newline + fmt.Sprintf("\t\treturn core.DateToString(bo.%s)", fieldID)
+				importUtils = true
+				setCase += newline + "\t\tif valueAsString == \"\" {" +
+					newline + fmt.Sprintf("\t\t\tbo.%s = nil", fieldID) +
+					newline + "\t\t\treturn nil" +
+					newline + "\t\t}"
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = core.StringToDate(valueAsString, \"%s\")", fieldID, fieldID)
+
+			case typeFamily == utils.TypeFamilyDATExONLY || typeFamily == utils.TypeFamilyTIMExOFxDAY:
+				parseFn := core.IfThenElse(typeFamily == utils.TypeFamilyDATExONLY, "ParseDateOnly", "ParseTimeOfDay")
+				getCase += newline + fmt.Sprintf("\t\treturn bo.%s.String()", fieldID)
+				setCase += newline + fmt.Sprintf("\t\tvalue, errParse := utils.%s(valueAsString)", parseFn) +
+					newline + "\t\tif errParse != nil {" +
+					newline + fmt.Sprintf("\t\t\treturn goald.ErrorC(errParse, \"Could not set '%s' to %%s\", valueAsString)", fieldID) +
+					newline + "\t\t}"
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = value", fieldID)
+
+			case typeFamily == utils.TypeFamilyDURATION:
+				getBit, setBit, end := getBits(fieldTypeAlias, "int64")
+				getCase += newline + fmt.Sprintf("\t\treturn core.Int64ToString(%sbo.%s%s)", getBit, fieldID, end)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %score.StringToInt64(valueAsString, \"%s\")%s", fieldID, setBit, fieldID, end)
+
+			case typeFamily == utils.TypeFamilyJSON || typeFamily == utils.TypeFamilyATTACHMENT:
+				getCase += getJSONGetCase(fieldID)
+				setCase += getJSONSetCase(fieldID, bObjectType.FieldByName(fieldName).Type().String())
+				importsMap["encoding/json"] = true
+
+			case typeFamily == utils.TypeFamilyBINARY:
+				getCase += newline + fmt.Sprintf("\t\treturn base64.StdEncoding.EncodeToString(bo.%s)", fieldID)
+				setCase += newline + "\t\tvalue, errDecode := base64.StdEncoding.DecodeString(valueAsString)" +
+					newline + "\t\tif errDecode != nil {" +
+					newline + fmt.Sprintf("\t\t\treturn goald.ErrorC(errDecode, \"Could not set '%s' from a non base64 string\")", fieldID) +
+					newline + "\t\t}"
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = value", fieldID)
+				importsMap["encoding/base64"] = true
+
+			case typeFamily == utils.TypeFamilyENUM && field.isMultiple():
+				enumType := bObjectType.FieldByName(fieldName).Type().Elem()
+				importsMap[enumType.PkgPath()] = true
+				getCase += newline + fmt.Sprintf("\t\treturn goald.EnumsToString(bo.%s)", fieldID)
+				setCase += newline + fmt.Sprintf("\t\tvalue, errParse := goald.StringToEnums[%s](valueAsString)", enumType.String()) +
+					newline + "\t\tif errParse != nil {" +
+					newline + fmt.Sprintf("\t\t\treturn goald.ErrorC(errParse, \"Could not set '%s' to %%s\", valueAsString)", fieldID) +
+					newline + "\t\t}"
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = value", fieldID)
+
+			case typeFamily == utils.TypeFamilyENUM:
+				getCase += newline + fmt.Sprintf("\t\treturn core.IntToString(bo.%s.Val())", fieldID)
+				importUtils = true
+				setCase += newline + fmt.Sprintf("\t\tbo.%s = %s(core.StringToInt(valueAsString, \"%s\"))", fieldID, fieldTypeAlias, fieldID)
+
+				setCase += newline + fmt.Sprintf("\t\tcore.PanicMsgIf(bo.%s.String() == \"\", \"Could not set '%s' to %%s since it's not a listed value\", valueAsString)",
+					fieldID, fieldID)
 			}
+
+			// appending the case
+			getCases = append(getCases, getCase)
+			setCases = append(setCases, setCase)
+		}
+	}
+
+	// the single relationships persisted on the class' table are valued with the ID of the targeted BO
+	for _, relationship := range boSpecs.base().getRelationshipsWithColumn() {
+		relName := relationship.getName()
+		relationshipID := fmt.Sprintf("(*%s.%s).%s", shortPkg, className, relName)
+
+		// e.g. "*otherpackage.OtherClass", which gives "&otherpackage.OtherClass{}" when instantiated
+		targetType := getRelatedType(bObjectType, relationship, importsMap)
+
+		getCase := fmt.Sprintf("\tcase \"%s\":", relName)
+		getCase += newline + fmt.Sprintf("\t\tif bo.%s == nil {", relationshipID) +
+			newline + "\t\t\treturn \"\"" +
+			newline + "\t\t}"
+		getCase += newline + fmt.Sprintf("\t\treturn string(bo.%s.GetID())", relationshipID)
+
+		setCase := fmt.Sprintf("\tcase \"%s\":", relName)
+		setCase += newline + "\t\tif valueAsString == \"\" {" +
+			newline + fmt.Sprintf("\t\t\tbo.%s = nil", relationshipID) +
+			newline + "\t\t\treturn nil" +
+			newline + "\t\t}"
+		if relationship.polymorphic {
+			// an interface cannot be instantiated, and the ID does not tell the targeted BO's class
+			setCase += newline + fmt.Sprintf("\t\treturn goald.Error(\"Cannot set polymorphic relationship '%s' from an ID only\")", relationshipID)
+		} else {
+			setCase += newline + fmt.Sprintf("\t\tbo.%s = &%s{}", relationshipID, strings.TrimPrefix(targetType, "*"))
+			setCase += newline + fmt.Sprintf("\t\tbo.%s.ID = goald.BObjID(valueAsString)", relationshipID)
 		}
+
+		getCases = append(getCases, getCase)
+		setCases = append(setCases, setCase)
+	}
+
+	// the multiple relationships are valued with slices of BOs, which we can count; and all the relationships
+	// can be valued with the BOs loaded along with this class' BOs
+	countCases := []string{}
+	relatedCases := []string{}
+	for _, relationship := range core.GetSortedValues(boSpecs.base().relationships) {
+		relName := relationship.getName()
+		relationshipID := fmt.Sprintf("(*%s.%s).%s", shortPkg, className, relName)
+		relatedType := getRelatedType(bObjectType, relationship, importsMap)
+		relatedCase := fmt.Sprintf("\tcase \"%s\":", relName)
+
+		if relationship.isMultiple() {
+			countCases = append(countCases, fmt.Sprintf("\tcase \"%s\":", relName)+
+				newline+fmt.Sprintf("\t\treturn len(bo.%s)", relationshipID))
+
+			relatedCase += newline + fmt.Sprintf("\t\tbo.%s = make([]%s, len(related))", relationshipID, relatedType) +
+				newline + "\t\tfor i, item := range related {" +
+				newline + fmt.Sprintf("\t\t\tbo.%s[i] = item.(%s)", relationshipID, relatedType) +
+				newline + "\t\t}"
+		} else {
+			relatedCase += newline + fmt.Sprintf("\t\tbo.%s = nil", relationshipID) +
+				newline + "\t\tif len(related) > 0 {" +
+				newline + fmt.Sprintf("\t\t\tbo.%s = related[0].(%s)", relationshipID, relatedType) +
+				newline + "\t\t}"
+		}
+
+		relatedCases = append(relatedCases, relatedCase+newline+"\t\treturn nil")
 	}
 
 	// handling the imports
 	content = strings.ReplaceAll(content, "$$getcases$$", strings.Join(getCases, newline))
 	content = strings.ReplaceAll(content, "$$setcases$$", strings.Join(setCases, newline))
+	content = strings.ReplaceAll(content, "$$countcases$$", strings.Join(countCases, newline))
+	content = strings.ReplaceAll(content, "$$relatedcases$$", strings.Join(relatedCases, newline))
 
 	if importUtils {
 		importsMap["github.com/aldesgroup/corego"] = true
@@ -239,3 +394,38 @@ func getNonBuiltInFieldType(bOjbType utils.GoaldType, fieldName string, toBeImpo
 
 	return fieldType.String() // e.g.: thatpackage.MyEnumType
 }
+
+// the type of the BOs the given relationship points to, e.g. "*otherpackage.OtherClass", or an interface for a polymorphic
+// relationship; its package is to be imported
+func getRelatedType(bObjType utils.GoaldType, relationship *Relationship, toBeImported map[string]bool) string {
+	relatedType := bObjType.FieldByName(relationship.getName()).Type()
+	if relationship.isMultiple() {
+		relatedType = relatedType.Elem()
+	}
+
+	if relatedPkg := relatedType.Indirect().PkgPath(); relatedPkg != "" && toBeImported != nil {
+		toBeImported[relatedPkg] = true
+	}
+
+	return relatedType.String()
+}
+
+// the body of the get case for a property handled as a JSON document
+func getJSONGetCase(fieldID string) string {
+	return newline + fmt.Sprintf("\t\tvalueAsBytes, errMarshal := json.Marshal(bo.%s)", fieldID) +
+		newline + "\t\tif errMarshal != nil {" +
+		newline + fmt.Sprintf("\t\t\tpanic(goald.ErrorC(errMarshal, \"Could not get '%s' as JSON\"))", fieldID) +
+		newline + "\t\t}" +
+		newline + "\t\treturn string(valueAsBytes)"
+}
+
+// the body of the set case for a property handled as a JSON document, of the given type
+func getJSONSetCase(fieldID, fieldType string) string {
+	return newline + fmt.Sprintf("\t\tvar value %s", fieldType) +
+		newline + "\t\tif valueAsString != \"\" {" +
+		newline + "\t\t\tif errUnmarshal := json.Unmarshal([]byte(valueAsString), &value); errUnmarshal != nil {" +
+		newline + fmt.Sprintf("\t\t\t\treturn goald.ErrorC(errUnmarshal, \"Could not set '%s' to %%s\", valueAsString)", fieldID) +
+		newline + "\t\t\t}" +
+		newline + "\t\t}" +
+		newline + fmt.Sprintf("\t\tbo.%s = value", fieldID)
+}
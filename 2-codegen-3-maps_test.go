@@ -0,0 +1,43 @@
+package goald
+
+import (
+	"testing"
+
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+type relatedTypeTestBO struct {
+	BusinessObject
+	Order  *routeTestOrder
+	Lines  []*routeTestOrderLine
+	Target IBusinessObject
+	Others []IBusinessObject
+}
+
+func TestGetRelatedType(t *testing.T) {
+	specs := NewBusinessObjectSpecs()
+	bObjType := utils.TypeOf(&relatedTypeTestBO{}, true)
+
+	tests := []struct {
+		name         string
+		relationship *Relationship
+		expected     string
+	}{
+		{"single", NewRelationship(specs, "Order", false, NewBusinessObjectSpecs()), "*goald.routeTestOrder"},
+		{"multiple", NewRelationship(specs, "Lines", true, NewBusinessObjectSpecs()), "*goald.routeTestOrderLine"},
+		{"polymorphic", NewRelationship(specs, "Target", false, NewBusinessObjectSpecs(), NewBusinessObjectSpecs()), "goald.IBusinessObject"},
+		{"polymorphic, multiple", NewRelationship(specs, "Others", true, NewBusinessObjectSpecs(), NewBusinessObjectSpecs()), "goald.IBusinessObject"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports := map[string]bool{}
+			if got := getRelatedType(bObjType, tt.relationship, imports); got != tt.expected {
+				t.Errorf("getRelatedType() = %s, expected %s", got, tt.expected)
+			}
+			if !imports["github.com/aldesgroup/goald"] {
+				t.Errorf("getRelatedType() imports = %v, expected the package of the related type", imports)
+			}
+		})
+	}
+}
@@ -19,35 +19,50 @@ import (
 // constants, variables, useful structs... & main generation function
 // ------------------------------------------------------------------------------------------------
 
-var (
-	modelsDIRPATH  = path.Join("src", "components", "models")
-	handledClasses = map[className]bool{}
-)
+var modelsDIRPATH = path.Join("src", "components", "models")
 
 const (
 	newFieldNAME = "newField"
 	newModelNAME = "newModel"
 )
 
+// what's needed to generate the model of a class in the client app
+type clientAppModel struct {
+	clsName       className                // the class we're generating the model for
+	endpointPath  string                   // the path of the first endpoint involving this class, if any
+	relationships map[string]*Relationship // the relationships loaded along with this class' BOs, through the app's endpoints
+}
+
 // TODO maybe do not collocate everything on the server... let's change the receiver here
 
 func (thisServer *server) generateAllClientAppModels(destdir string, regen bool, isWebapp bool) {
+	// the models to generate
+	models := map[className]*clientAppModel{}
+
 	// the enum files to generate
 	enums := map[string]IEnum{}
 
 	// scanning for BOs involved in endpoints used from the web app
 	for _, ep := range restRegistry.endpoints {
 		if (isWebapp && ep.isCalledFromWebApp()) || (!isWebapp && ep.isCalledFromNativeApp()) {
-			// generating the model for the endpoint resource
-			thisServer.generateClientAppModel(destdir, ep, false, enums, regen, isWebapp)
+			endpointPath := thisServer.config.commonPart().HTTP.ApiPath + getLatestVersionPrefix(ep) + ep.getFullPath()
+
+			// we need the model for the endpoint resource, along with the relationships loaded with it
+			resourceSpecs := specsForName(ep.getResourceClass())
+			addClientAppModel(models, ep.getResourceClass(), endpointPath, resourceSpecs.getLoadingScenarios(ep.getLoadingType()))
 
 			// if the endpoint admits a BO as an input (body or URL params), then we also need the model in the webapp
 			if ep.getInputOrParamsClass() != "" {
-				thisServer.generateClientAppModel(destdir, ep, true, enums, regen, isWebapp)
+				addClientAppModel(models, ep.getInputOrParamsClass(), endpointPath, nil)
 			}
 		}
 	}
 
+	// models generation
+	for _, clsName := range core.GetSortedKeys(models) {
+		thisServer.generateClientAppModel(destdir, models[clsName], enums, regen, isWebapp)
+	}
+
 	// enum files generation
 	for enumType, enum := range enums {
 		// TODO run in go routines
@@ -55,6 +70,30 @@ func (thisServer *server) generateAllClientAppModels(destdir string, regen bool,
 	}
 }
 
+// registering the need for the given class' model, and recursively, for the models of the classes
+// pointed by the relationships loaded with it
+func addClientAppModel(models map[className]*clientAppModel, clsName className, endpointPath string, scenarios []*LoadingScenario) {
+	model := models[clsName]
+	if model == nil {
+		model = &clientAppModel{clsName: clsName, relationships: map[string]*Relationship{}}
+		models[clsName] = model
+	}
+
+	// the model is linked with the first endpoint found for its class
+	if model.endpointPath == "" {
+		model.endpointPath = endpointPath
+	}
+
+	for _, scenario := range scenarios {
+		model.relationships[scenario.relationship.name] = scenario.relationship
+
+		// the related classes are not necessarily served through their own endpoints
+		for _, target := range scenario.relationship.targets {
+			addClientAppModel(models, target.base().name, "", scenario.with)
+		}
+	}
+}
+
 type codeContext struct {
 	enums      map[string]IEnum
 	bObjType   utils.GoaldType
@@ -62,21 +101,17 @@ type codeContext struct {
 }
 
 func (ctx *codeContext) getEnumType(field IField) string {
+	if field.isMultiple() {
+		return ctx.bObjType.FieldByName(field.getName()).Type().Elem().Name()
+	}
+
 	return ctx.bObjType.FieldByName(field.getName()).Type().Name()
 }
 
-func (thisServer *server) generateClientAppModel(destdir string, ep iEndpoint, useInputClass bool,
+func (thisServer *server) generateClientAppModel(destdir string, model *clientAppModel,
 	enums map[string]IEnum, regen bool, isWebapp bool) {
 	// which model to generate?
-	clsName := core.IfThenElse(useInputClass, ep.getInputOrParamsClass(), ep.getResourceClass())
-
-	// already done this?
-	if handledClasses[clsName] {
-		return
-	}
-
-	// but we're doing this now
-	handledClasses[clsName] = true
+	clsName := model.clsName
 
 	// the business object we're dealing with
 	boSpecs := specsForName(clsName)
@@ -98,7 +133,11 @@ func (thisServer *server) generateClientAppModel(destdir string, ep iEndpoint, u
 	// gathering the needed enums
 	for _, field := range boFields {
 		if field.getTypeFamily() == utils.TypeFamilyENUM {
-			enums[codeCtx.getEnumType(field)] = codeCtx.boInstance.GetFieldValue(field.getName()).(IEnum)
+			if field.isMultiple() {
+				enums[codeCtx.getEnumType(field)] = codeCtx.boInstance.GetFieldItemZeroValue(field.getName()).(IEnum)
+			} else {
+				enums[codeCtx.getEnumType(field)] = codeCtx.boInstance.GetFieldValue(field.getName()).(IEnum)
+			}
 		}
 	}
 
@@ -108,13 +147,21 @@ func (thisServer *server) generateClientAppModel(destdir string, ep iEndpoint, u
 	}
 
 	// getting the file content - which might be empty if the file does not exist yet
-	code := parseCode(filepath).initFixedBlocks(modelName, thisServer.config.commonPart().HTTP.ApiPath+ep.getFullPath(), isWebapp)
+	code := parseCode(filepath).initFixedBlocks(modelName, model.endpointPath, isWebapp)
 
 	// browsing the entity's properties to fill the get / set cases in the 2 switch
 	for _, field := range boFields {
 		code.addFieldIfNeeded(codeCtx, field)
 	}
 
+	// the relationships loaded along with the BOs, as nested models
+	for _, relationship := range core.GetSortedValues(model.relationships) {
+		code.addRelationshipIfNeeded(relationship)
+	}
+
+	// describing the rules involving several properties, so they can be checked on the client side too
+	code.addRulesIfNeeded(boSpecs)
+
 	// "unpacking" the code blocks to code lines
 	codeLines := []string{}
 	for _, block := range code.blocks {
@@ -155,8 +202,8 @@ func (thisCode *codeFile) initFixedBlocks(modelName string, endpointPath string,
 func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 	// adding to the context, and the class file content
 	if typeFamily := field.getTypeFamily(); typeFamily != utils.TypeFamilyUNKNOWN && typeFamily != utils.TypeFamilyRELATIONSHIPxMONOM {
-		// not handling multiple properties for now - nor the ID field
-		if !field.isMultiple() && field.getName() != "ID" {
+		// not handling the ID field
+		if field.getName() != "ID" {
 			var (
 				enumType, enumVar, initVal, fieldAtomType string
 			)
@@ -175,16 +222,51 @@ func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 					thisCode.addEnumImport(enumVar)
 				}
 
-				// setting the field atom's type
-				fieldAtomType = fmt.Sprintf("<%s.%s>", enumVar, enumType)
+				// setting the field atom's type, and proposing an init value
+				if field.isMultiple() {
+					fieldAtomType = fmt.Sprintf("<%s.%s[]>", enumVar, enumType)
+					initVal = "[]"
+				} else {
+					fieldAtomType = fmt.Sprintf("<%s.%s>", enumVar, enumType)
+					initVal = fmt.Sprintf("%s.%s", enumVar, makeEnumName(core.GetFirstMapValue(codeCtx.enums[enumType].Values())))
+				}
+
+			// --- numbers -----------------------------------------------------------------
+			case utils.TypeFamilyINT, utils.TypeFamilyBIGINT, utils.TypeFamilyREAL, utils.TypeFamilyDOUBLE, utils.TypeFamilyDURATION:
+				// proposing an init value - durations being numbers of nanoseconds
+				initVal = "0"
+
+			// --- decimals ----------------------------------------------------------------
+			case utils.TypeFamilyDECIMAL:
+				// exact decimal numbers are passed as strings, not to lose any digit
+				fieldAtomType = "<string>"
 
 				// proposing an init value
-				initVal = fmt.Sprintf("%s.%s", enumVar, makeEnumName(core.GetFirstMapValue(codeCtx.enums[enumType].Values())))
+				initVal = "'0'"
+
+			// --- JSON documents ----------------------------------------------------------
+			case utils.TypeFamilyJSON:
+				// the document is passed through as is
+				fieldAtomType = "<any>"
 
-			// --- numbers -----------------------------------------------------------------
-			case utils.TypeFamilyINT, utils.TypeFamilyBIGINT, utils.TypeFamilyREAL, utils.TypeFamilyDOUBLE:
 				// proposing an init value
-				initVal = "0"
+				initVal = "null"
+
+			// --- binary data -------------------------------------------------------------
+			case utils.TypeFamilyBINARY:
+				// the bytes are passed as a base64 string
+				fieldAtomType = "<string>"
+
+				// proposing an init value
+				initVal = "''"
+
+			// --- attached files ----------------------------------------------------------
+			case utils.TypeFamilyATTACHMENT:
+				// only the metadata are passed, the content being uploaded / downloaded through dedicated endpoints
+				fieldAtomType = "<{ Name: string; Size: number; ContentType: string; Checksum: string } | null>"
+
+				// proposing an init value
+				initVal = "null"
 
 			// --- booleans ----------------------------------------------------------------
 			case utils.TypeFamilyBOOL:
@@ -199,9 +281,33 @@ func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 				// proposing an init value
 				initVal = "null"
 
+			// --- dates without time ------------------------------------------------------
+			case utils.TypeFamilyDATExONLY:
+				// passed as strings like "2024-12-31", so as not to be shifted by any timezone
+				fieldAtomType = "<string | null>"
+
+				// proposing an init value
+				initVal = "null"
+
+			// --- times of day ------------------------------------------------------------
+			case utils.TypeFamilyTIMExOFxDAY:
+				// passed as strings like "08:30:00"
+				fieldAtomType = "<string>"
+
+				// proposing an init value
+				initVal = "'00:00:00'"
+
 				// SWITCH END
 			}
 
+			// the multiple primitive values are passed as arrays
+			if field.isMultiple() && typeFamily != utils.TypeFamilyENUM {
+				itemType := core.IfThenElse(typeFamily == utils.TypeFamilySTRING, "string",
+					core.IfThenElse(typeFamily == utils.TypeFamilyBOOL, "boolean", "number"))
+				fieldAtomType = fmt.Sprintf("<%s[]>", itemType)
+				initVal = "[]"
+			}
+
 			// adding the field name to the model block if needed
 			if !thisCode.blockHasLineStartingWith(newModelNAME, field.getName()+":") {
 				thisCode.insertLineIntoBlockBeforePrefix(newModelNAME, fmt.Sprintf("    %s,", field.getName()), "}")
@@ -216,6 +322,11 @@ func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 				newBlock.appendLine("});", true)
 			}
 
+			// several values can be set here
+			if field.isMultiple() && (missingField || !thisCode.blockHasLineStartingWith(field.getName(), "multiple:")) {
+				thisCode.insertLineIntoBlockBeforePrefix(field.getName(), "    multiple: true,", "}")
+			}
+
 			// linking the enum's options to the field, if needed
 			if typeFamily == utils.TypeFamilyENUM {
 				if missingField || !thisCode.blockHasLineStartingWith(field.getName(), "options:") {
@@ -262,6 +373,12 @@ func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 				}
 			}
 
+			// handling the constraints - for decimal fields
+			if df, ok := field.(*DecimalField); ok {
+				thisCode.updateLineIntoBlockWithPrefix(field.getName(), fmt.Sprintf("    precision: %d,", df.precision), "precision:", "}")
+				thisCode.updateLineIntoBlockWithPrefix(field.getName(), fmt.Sprintf("    scale: %d,", df.scale), "scale:", "}")
+			}
+
 			// handling the constraints - for string fields
 			if sf, ok := field.(*StringField); ok {
 				if sf.size > 0 {
@@ -270,6 +387,25 @@ func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 				if sf.atLeast > 0 {
 					thisCode.updateLineIntoBlockWithPrefix(field.getName(), fmt.Sprintf("    min: %d,", sf.atLeast), "min:", "}")
 				}
+				if sf.pattern != nil {
+					regex := strings.ReplaceAll(sf.pattern.String(), "/", "\\/")
+					thisCode.updateLineIntoBlockWithPrefix(field.getName(), fmt.Sprintf("    pattern: /%s/,", regex), "pattern:", "}")
+				}
+				if sf.email {
+					thisCode.updateLineIntoBlockWithPrefix(field.getName(), "    email: true,", "email:", "}")
+				}
+				if sf.url {
+					thisCode.updateLineIntoBlockWithPrefix(field.getName(), "    url: true,", "url:", "}")
+				}
+				if len(sf.oneOf) > 0 {
+					oneOf := core.MapFn(sf.oneOf, func(value string) string { return "'" + strings.ReplaceAll(value, "'", "\\'") + "'" })
+					thisCode.updateLineIntoBlockWithPrefix(field.getName(), fmt.Sprintf("    oneOf: [%s],", strings.Join(oneOf, ", ")), "oneOf:", "}")
+				}
+				if len(sf.validators) > 0 {
+					// the apps have to provide validators with the same names
+					names := core.MapFn(sf.validators, func(validator *customValidator) string { return "'" + validator.name + "'" })
+					thisCode.updateLineIntoBlockWithPrefix(field.getName(), fmt.Sprintf("    custom: [%s],", strings.Join(names, ", ")), "custom:", "}")
+				}
 			}
 
 			// handling the constraints - misc
@@ -278,10 +414,84 @@ func (thisCode *codeFile) addFieldIfNeeded(codeCtx *codeContext, field IField) {
 			} else {
 				thisCode.updateLineIntoBlockWithPrefix(field.getName(), "    mandatory: false,", "mandatory: true", "")
 			}
+
+			// the fields that can be sent in a PATCH request
+			if field.isPatchable() {
+				thisCode.updateLineIntoBlockWithPrefix(field.getName(), "    patchable: true,", "patchable:", "}")
+			}
+		}
+	}
+}
+
+// describing the rules involving several properties within the model
+func (thisCode *codeFile) addRulesIfNeeded(boSpecs IBusinessObjectSpecs) {
+	if rules := boSpecs.base().rules; len(rules) > 0 {
+		descriptions := core.MapFn(rules, func(rule iSpecsRule) string { return rule.describe() })
+		thisCode.updateLineIntoBlockWithPrefix(newModelNAME, fmt.Sprintf("    rules: [%s],", strings.Join(descriptions, ", ")), "rules:", "}")
+	} else {
+		thisCode.updateLineIntoBlockWithPrefix(newModelNAME, "    rules: [],", "rules:", "")
+	}
+}
+
+// handling a relationship loaded along with the BOs, adding it as a nested model - or an array of nested models - if needed
+func (thisCode *codeFile) addRelationshipIfNeeded(relationship *Relationship) {
+	// importing the related models if needed
+	targetModels := []string{}
+	for _, target := range relationship.targets {
+		targetModel := core.PascalToCamel(string(target.base().name))
+		if thisCode.blocksMap[modelImportID(targetModel)] == nil {
+			thisCode.addModelImport(targetModel)
 		}
+		targetModels = append(targetModels, targetModel)
+	}
+
+	// adding the relationship name to the model block if needed
+	if !thisCode.blockHasLineStartingWith(newModelNAME, relationship.getName()+":") {
+		thisCode.insertLineIntoBlockBeforePrefix(newModelNAME, fmt.Sprintf("    %s,", relationship.getName()), "}")
+	}
+
+	// adding the field if needed
+	missingField := thisCode.blocksMap[relationship.getName()] == nil
+	if missingField {
+		fieldAtomType, initVal := core.IfThenElse(relationship.isMultiple(), "<object[]>", "<object | null>"),
+			core.IfThenElse(relationship.isMultiple(), "[]", "null")
+		fieldDecl := fmt.Sprintf("const %s = "+newFieldNAME+"%s('%s', {", relationship.getName(), fieldAtomType, relationship.getName())
+		newBlock := thisCode.addNewBlockBeforeEndPosition(fieldDecl, true, relationship.getName(), true, 1)
+		newBlock.appendLine(fmt.Sprintf("    initialValue: %s,", initVal), true)
+		newBlock.appendLine("});", true)
+	}
+
+	// several BOs can be pointed here
+	if relationship.isMultiple() && (missingField || !thisCode.blockHasLineStartingWith(relationship.getName(), "multiple:")) {
+		thisCode.insertLineIntoBlockBeforePrefix(relationship.getName(), "    multiple: true,", "}")
+	}
+
+	// linking the nested model(s) to the field - a polymorphic relationship pointing to several models
+	if relationship.polymorphic {
+		thisCode.updateLineIntoBlockWithPrefix(relationship.getName(), fmt.Sprintf("    models: [%s],", strings.Join(targetModels, ", ")), "models:", "}")
+	} else {
+		thisCode.updateLineIntoBlockWithPrefix(relationship.getName(), fmt.Sprintf("    model: %s,", targetModels[0]), "model:", "}")
+	}
+
+	// handling the constraints
+	if relationship.isMandatory() {
+		thisCode.updateLineIntoBlockWithPrefix(relationship.getName(), "    mandatory: true,", "mandatory:", "}")
+	} else {
+		thisCode.updateLineIntoBlockWithPrefix(relationship.getName(), "    mandatory: false,", "mandatory: true", "")
 	}
 }
 
+// adding an import for another model
+func (thisCode *codeFile) addModelImport(modelName string) {
+	modelImport := fmt.Sprintf("import { %s } from './%s';", modelName, modelName)
+	thisCode.addNewBlockAtPosition(modelImport, true, modelImportID(modelName), false, thisCode.findLastImportPosition())
+}
+
+// the ID of the block importing the given model, which should not clash with the IDs of the field blocks
+func modelImportID(modelName string) string {
+	return "import:" + modelName
+}
+
 // adding an import for an enum
 func (thisCode *codeFile) addEnumImport(enumVar string) {
 	enumImport := fmt.Sprintf("import * as %s from \"./%s\"", enumVar, enumVar)
@@ -601,6 +811,10 @@ func parseCode(filepath string) (code *codeFile) {
 		case strings.HasPrefix(content, "import"):
 			// using the import alias as an ID
 			importID := core.IfThenElse(strings.HasPrefix(content, "import * as _"), extractBlockID(content, 12, "from"), "")
+			if strings.HasPrefix(content, "import {") && strings.Contains(content, "from './") {
+				// the import of another model
+				importID = modelImportID(extractBlockID(content, 8, "}"))
+			}
 			code.addNewBlock(rawline, true, importID, false)
 
 		// case strings.HasPrefix(content, "const") && strings.Contains(content, "fieldAtom("):
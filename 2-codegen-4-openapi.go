@@ -0,0 +1,508 @@
+// ------------------------------------------------------------------------------------------------
+// Here is the code used for generating the OpenAPI document describing the REST API, from the
+// endpoint registry & the BO specs; it's written at codegen time, and can be served by the server
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+const (
+	openAPIVERSION      = "3.1.0"        // the version of the OpenAPI specification we follow
+	openAPIFILExNAME    = "openapi.json" // the file generated in the source directory
+	openAPIxSCHEMAS     = "#/components/schemas/"
+	openAPIxRESPONSE    = "Response"   // the name of the schema describing the envelope of all our JSON responses
+	openAPIxATTACHMENT  = "Attachment" // the name of the schema describing an attached file's metadata
+	openAPIxFIELDxERROR = "FieldError" // the name of the schema describing 1 violation of the specs
+)
+
+// ------------------------------------------------------------------------------------------------
+// The OpenAPI document structure - only the parts we need
+// ------------------------------------------------------------------------------------------------
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       *openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components *openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	Summary     string                      `json:"summary,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"` // "path", "query" or "header"
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+// a JSON schema, as used in OpenAPI 3.1
+type openAPISchema struct {
+	Ref             string                    `json:"$ref,omitempty"`
+	Type            string                    `json:"type,omitempty"`
+	Format          string                    `json:"format,omitempty"`
+	ContentEncoding string                    `json:"contentEncoding,omitempty"`
+	Title           string                    `json:"title,omitempty"`
+	Description     string                    `json:"description,omitempty"`
+	Const           any                       `json:"const,omitempty"`
+	Enum            []string                  `json:"enum,omitempty"`
+	Pattern         string                    `json:"pattern,omitempty"`
+	MinLength       int                       `json:"minLength,omitempty"`
+	MaxLength       int                       `json:"maxLength,omitempty"`
+	Minimum         any                       `json:"minimum,omitempty"`
+	Maximum         any                       `json:"maximum,omitempty"`
+	Items           *openAPISchema            `json:"items,omitempty"`
+	Properties      map[string]*openAPISchema `json:"properties,omitempty"`
+	Required        []string                  `json:"required,omitempty"`
+	OneOf           []*openAPISchema          `json:"oneOf,omitempty"`
+	AllOf           []*openAPISchema          `json:"allOf,omitempty"`
+}
+
+func refTo(schemaName string) *openAPISchema {
+	return &openAPISchema{Ref: openAPIxSCHEMAS + schemaName}
+}
+
+func arrayOf(items *openAPISchema) *openAPISchema {
+	return &openAPISchema{Type: "array", Items: items}
+}
+
+// ------------------------------------------------------------------------------------------------
+// Generating & serving the document
+// ------------------------------------------------------------------------------------------------
+
+// writes the OpenAPI document describing the API into the source directory
+func (thisServer *server) generateOpenAPIDocument(srcdir string) {
+	core.WriteToFile(string(thisServer.getOpenAPIDocumentBytes()), path.Join(srcdir, openAPIFILExNAME))
+}
+
+// returns a handler serving the OpenAPI document, built once and for all
+func (thisServer *server) handleOpenAPIDocument() http.HandlerFunc {
+	docBytes := thisServer.getOpenAPIDocumentBytes()
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if _, errWrite := w.Write(docBytes); errWrite != nil {
+			slog.Error(fmt.Sprintf("Error while writing out the OpenAPI document: %s", errWrite))
+		}
+	}
+}
+
+func (thisServer *server) getOpenAPIDocumentBytes() []byte {
+	docBytes, errMarshal := json.MarshalIndent(thisServer.buildOpenAPIDocument(), "", "  ")
+	core.PanicMsgIfErr(errMarshal, "Could not marshal the OpenAPI document")
+
+	return docBytes
+}
+
+// builds the OpenAPI document from all the registered endpoints
+func (thisServer *server) buildOpenAPIDocument() *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI:    openAPIVERSION,
+		Info:       &openAPIInfo{Title: string(getCurrentModuleName()), Version: getOpenAPIVersion()},
+		Paths:      map[string]map[string]*openAPIOperation{},
+		Components: &openAPIComponents{Schemas: getOpenAPICommonSchemas()},
+	}
+
+	apiPath := thisServer.config.commonPart().HTTP.ApiPath
+	latest := getLatestAPIVersion()
+	for _, ep := range restRegistry.endpoints {
+		// the unversioned endpoints are described once, the versioned ones once per version they're served in
+		versionPrefixes := core.MapFn(getServedVersions(ep, latest), func(version int) string { return apiVersionPREFIX + strconv.Itoa(version) })
+		if len(versionPrefixes) == 0 {
+			versionPrefixes = []string{""}
+		}
+
+		for _, versionPrefix := range versionPrefixes {
+			opPath := apiPath + versionPrefix + pathParamREGEX.ReplaceAllString(ep.getFullPath(), "{$1}")
+			if doc.Paths[opPath] == nil {
+				doc.Paths[opPath] = map[string]*openAPIOperation{}
+			}
+
+			doc.Paths[opPath][strings.ToLower(ep.getMethod())] = buildOpenAPIOperation(ep, doc.Components.Schemas)
+		}
+	}
+
+	return doc
+}
+
+// the path params, written like ":id" by our router, and like "{id}" in OpenAPI
+var pathParamREGEX = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// the version of the main module, if known
+func getOpenAPIVersion() string {
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		return bi.Main.Version
+	}
+
+	return "0.0.0"
+}
+
+// describes 1 endpoint, and adds the schemas of the classes involved to the given ones
+func buildOpenAPIOperation(ep iEndpoint, schemas map[string]*openAPISchema) *openAPIOperation {
+	resourceSchema := addOpenAPIClassSchema(specsForName(ep.getResourceClass()), schemas)
+
+	op := &openAPIOperation{
+		Summary:    ep.getLabel(),
+		Tags:       []string{string(ep.getResourceClass())},
+		Responses:  map[string]*openAPIResponse{},
+		Deprecated: ep.isDeprecated(),
+	}
+
+	// the parent of the targeted BO(s), for a nested endpoint
+	if childToParent := ep.getParentRelationship(); childToParent != nil {
+		op.Parameters = append(op.Parameters, &openAPIParameter{
+			Name:     ep.getParentIDParam(),
+			In:       "path",
+			Required: true,
+			Schema:   getOpenAPIFieldSchema(childToParent.targets[0].ID(), schemas),
+		})
+	}
+
+	// the targeted BO
+	if idProp := ep.getIDProp(); idProp != nil {
+		op.Parameters = append(op.Parameters, &openAPIParameter{
+			Name:     ep.getIDParam(),
+			In:       "path",
+			Required: true,
+			Schema:   getOpenAPIFieldSchema(idProp, schemas),
+		})
+	}
+
+	// the input, if any
+	switch {
+	case ep.isFileUpload():
+		op.RequestBody = &openAPIRequestBody{Required: true, Content: map[string]*openAPIMediaType{
+			"multipart/form-data": {Schema: &openAPISchema{
+				Type:       "object",
+				Properties: map[string]*openAPISchema{uploadFORMxKEY: {Type: "string", ContentEncoding: "binary"}},
+				Required:   []string{uploadFORMxKEY},
+			}},
+		}}
+
+	case ep.isPatch():
+		op.RequestBody = &openAPIRequestBody{Required: true, Content: map[string]*openAPIMediaType{
+			"application/json": {Schema: getOpenAPIPatchSchema(specsForName(ep.getResourceClass()), schemas)},
+		}}
+
+	case ep.hasBodyOrParamsInput() && ep.isBodyInputRequired():
+		inputSchema := addOpenAPIClassSchema(specsForName(ep.getInputOrParamsClass()), schemas)
+		op.RequestBody = &openAPIRequestBody{Required: true, Content: map[string]*openAPIMediaType{
+			"application/json": {Schema: core.IfThenElse(ep.isMultipleInput(), arrayOf(inputSchema), inputSchema)},
+		}}
+
+	case ep.hasBodyOrParamsInput():
+		// the URL query params
+		for _, field := range core.GetSortedValues(specsForName(ep.getInputOrParamsClass()).base().fields) {
+			if field.getName() != "ID" {
+				op.Parameters = append(op.Parameters, &openAPIParameter{
+					Name:     field.getName(),
+					In:       "query",
+					Required: field.isMandatory() && field.getDefaultValue() == "",
+					Schema:   getOpenAPIFieldSchema(field, schemas),
+				})
+			}
+		}
+	}
+
+	// the properties to send back, for a GET request
+	if ep.getMethod() == http.MethodGet && !ep.isFileDownload() {
+		op.Parameters = append(op.Parameters, &openAPIParameter{
+			Name: fieldsQUERYxPARAM,
+			In:   "query",
+			Schema: &openAPISchema{
+				Type:        "string",
+				Description: "The properties to send back, separated by commas, e.g. 'ID,Name'",
+			},
+		})
+	}
+
+	// the output
+	if ep.isFileDownload() {
+		op.Responses["200"] = &openAPIResponse{Description: "The requested file", Content: map[string]*openAPIMediaType{
+			defaultCONTENTxTYPE: {Schema: &openAPISchema{Type: "string", ContentEncoding: "binary"}},
+		}}
+	} else {
+		output := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+		if ep.isMultipleOutput() {
+			output.Properties["ObjectList"] = arrayOf(resourceSchema)
+		} else {
+			output.Properties["Object"] = resourceSchema
+		}
+
+		op.Responses["2XX"] = &openAPIResponse{Description: "Success", Content: map[string]*openAPIMediaType{
+			"application/json": {Schema: &openAPISchema{AllOf: []*openAPISchema{refTo(openAPIxRESPONSE), output}}},
+		}}
+	}
+
+	// the conditional requests
+	if ep.isConditional() {
+		switch ep.getMethod() {
+		case http.MethodGet:
+			op.Parameters = append(op.Parameters,
+				&openAPIParameter{Name: ifNoneMatchHEADER, In: "header", Schema: &openAPISchema{Type: "string"}},
+				&openAPIParameter{Name: ifModifiedSinceHEADER, In: "header", Schema: &openAPISchema{Type: "string"}})
+			op.Responses["304"] = &openAPIResponse{Description: "Not modified since the client got it"}
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			op.Parameters = append(op.Parameters,
+				&openAPIParameter{Name: ifMatchHEADER, In: "header", Schema: &openAPISchema{Type: "string"}})
+			op.Responses["412"] = &openAPIResponse{Description: "Modified since the client got it", Content: map[string]*openAPIMediaType{
+				"application/json": {Schema: refTo(openAPIxRESPONSE)},
+			}}
+		}
+	}
+
+	// the idempotent requests
+	if ep.isIdempotent() {
+		op.Parameters = append(op.Parameters, &openAPIParameter{Name: idempotencyKeyHEADER, In: "header", Schema: &openAPISchema{
+			Type:        "string",
+			MaxLength:   idempotencyKeyMAXxLENGTH,
+			Description: "A key identifying the request, e.g. a UUID, to send again when retrying it, so it's not handled twice",
+		}})
+	}
+
+	op.Responses["default"] = &openAPIResponse{Description: "Failure", Content: map[string]*openAPIMediaType{
+		"application/json": {Schema: refTo(openAPIxRESPONSE)},
+	}}
+
+	return op
+}
+
+// ------------------------------------------------------------------------------------------------
+// Schemas
+// ------------------------------------------------------------------------------------------------
+
+// the schemas that do not depend on the BO classes
+func getOpenAPICommonSchemas() map[string]*openAPISchema {
+	return map[string]*openAPISchema{
+		openAPIxRESPONSE: {
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"Object":     {Description: "the returned BO, if any"},
+				"ObjectList": {Type: "array", Description: "the returned BOs, if any"},
+				"StatusCode": {Type: "integer", Description: "the HTTP status code"},
+				"Status":     {Type: "string", Description: "the HTTP status"},
+				"Message":    {Type: "string"},
+				"Details":    {Description: "more details, e.g. the list of FieldError objects when the specs are violated"},
+			},
+			Required: []string{"StatusCode", "Status", "Message"},
+		},
+		openAPIxFIELDxERROR: {
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"Field": {Type: "string"},
+				"Rule":  {Type: "string"},
+				"Limit": {Type: "string"},
+			},
+			Required: []string{"Field", "Rule"},
+		},
+		openAPIxATTACHMENT: {
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"Name":        {Type: "string"},
+				"Size":        {Type: "integer", Format: "int64"},
+				"ContentType": {Type: "string"},
+				"Checksum":    {Type: "string"},
+			},
+		},
+	}
+}
+
+// adds the schema of the given class - and of the classes it points to - to the given schemas, if not done yet,
+// and returns a reference to it
+func addOpenAPIClassSchema(boSpecs IBusinessObjectSpecs, schemas map[string]*openAPISchema) *openAPISchema {
+	clsName := string(boSpecs.base().name)
+	if schemas[clsName] != nil {
+		return refTo(clsName)
+	}
+
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	schemas[clsName] = schema // registered before going through the relationships, which can be circular
+
+	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
+		schema.Properties[field.getName()] = getOpenAPIFieldSchema(field, schemas)
+		if field.isMandatory() {
+			schema.Required = append(schema.Required, field.getName())
+		}
+	}
+
+	for _, relationship := range core.GetSortedValues(boSpecs.base().relationships) {
+		targets := core.MapFn(relationship.targets, func(target IBusinessObjectSpecs) *openAPISchema {
+			return addOpenAPIClassSchema(target, schemas)
+		})
+
+		target := &openAPISchema{OneOf: targets}
+		if len(targets) == 1 {
+			target = targets[0]
+		}
+
+		schema.Properties[relationship.getName()] = core.IfThenElse(relationship.isMultiple(), arrayOf(target), target)
+	}
+
+	return refTo(clsName)
+}
+
+// the schema of a patch for the given class, i.e. an object with only the patchable fields
+func getOpenAPIPatchSchema(boSpecs IBusinessObjectSpecs, schemas map[string]*openAPISchema) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
+		if field.isPatchable() {
+			schema.Properties[field.getName()] = getOpenAPIFieldSchema(field, schemas)
+		}
+	}
+
+	return schema
+}
+
+// the schema of 1 field, with the constraints declared in the specs
+func getOpenAPIFieldSchema(field IField, schemas map[string]*openAPISchema) *openAPISchema {
+	// the IDs are integers generated by the DB, or UUIDs
+	if field.getName() == "ID" {
+		if field.ownerSpecs().getIDStrategy().isUUID() {
+			return &openAPISchema{Type: "string", Format: "uuid"}
+		}
+
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	}
+
+	schema := &openAPISchema{}
+
+	switch f := field.(type) {
+	case *StringField:
+		schema.Type = "string"
+		schema.MaxLength = f.size
+		schema.MinLength = f.atLeast
+		schema.Enum = f.oneOf
+		if f.pattern != nil {
+			schema.Pattern = f.pattern.String()
+		}
+		if f.email {
+			schema.Format = "email"
+		} else if f.url {
+			schema.Format = "uri"
+		}
+
+	case *IntField:
+		schema.Type, schema.Format = "integer", "int32"
+		schema.Minimum = core.IfThenElse[any](f.minSet, f.min, nil)
+		schema.Maximum = core.IfThenElse[any](f.maxSet, f.max, nil)
+
+	case *BigIntField:
+		schema.Type, schema.Format = "integer", "int64"
+		schema.Minimum = core.IfThenElse[any](f.minSet, f.min, nil)
+		schema.Maximum = core.IfThenElse[any](f.maxSet, f.max, nil)
+
+	case *RealField:
+		schema.Type, schema.Format = "number", "float"
+		schema.Minimum = core.IfThenElse[any](f.minSet, f.min, nil)
+		schema.Maximum = core.IfThenElse[any](f.maxSet, f.max, nil)
+
+	case *DoubleField:
+		schema.Type, schema.Format = "number", "double"
+		schema.Minimum = core.IfThenElse[any](f.minSet, f.min, nil)
+		schema.Maximum = core.IfThenElse[any](f.maxSet, f.max, nil)
+
+	case *EnumField:
+		schema = refTo(addOpenAPIEnumSchema(f, schemas))
+
+	default:
+		schema = getOpenAPITypeFamilySchema(field)
+	}
+
+	if field.isMultiple() {
+		return arrayOf(schema)
+	}
+
+	return schema
+}
+
+// the schema of the fields with no specific constraint, given their type family
+func getOpenAPITypeFamilySchema(field IField) *openAPISchema {
+	switch field.getTypeFamily() {
+	case utils.TypeFamilyBOOL:
+		return &openAPISchema{Type: "boolean"}
+	case utils.TypeFamilyDECIMAL:
+		return &openAPISchema{Type: "string", Format: "decimal"}
+	case utils.TypeFamilyDATE:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case utils.TypeFamilyDATExONLY:
+		return &openAPISchema{Type: "string", Format: "date"}
+	case utils.TypeFamilyTIMExOFxDAY:
+		return &openAPISchema{Type: "string", Format: "time"}
+	case utils.TypeFamilyDURATION:
+		return &openAPISchema{Type: "integer", Format: "int64", Description: "nanoseconds"}
+	case utils.TypeFamilyBINARY:
+		return &openAPISchema{Type: "string", ContentEncoding: "base64"}
+	case utils.TypeFamilyATTACHMENT:
+		return refTo(openAPIxATTACHMENT)
+	default:
+		// any JSON value
+		return &openAPISchema{}
+	}
+}
+
+// adds the schema of the given field's enum type to the given schemas, if not done yet, and returns its name
+func addOpenAPIEnumSchema(enumField *EnumField, schemas map[string]*openAPISchema) string {
+	if schemas[enumField.enumName] == nil {
+		enum := getFieldEnum(getClass(enumField.ownerSpecs()), enumField)
+		schemas[enumField.enumName] = &openAPISchema{
+			Type: "integer",
+			OneOf: core.MapFn(core.GetSortedKeys(enum.Values()), func(val int) *openAPISchema {
+				return &openAPISchema{Const: val, Title: enum.Values()[val]}
+			}),
+		}
+	}
+
+	return enumField.enumName
+}
+
+// returns the zero value of the enum type of the given field, which gives access to all the enum values
+func getFieldEnum(boClass IClass, field IField) IEnum {
+	boInstance := utils.ValueOf(boClass.NewObject())
+	if field.isMultiple() {
+		return boInstance.GetFieldItemZeroValue(field.getName()).(IEnum)
+	}
+
+	return boInstance.GetFieldValue(field.getName()).(IEnum)
+}
@@ -7,6 +7,8 @@ package goald
 import (
 	"fmt"
 	"log/slog"
+	"regexp"
+	"strings"
 	"time"
 
 	core "github.com/aldesgroup/corego"
@@ -19,6 +21,8 @@ func (thisServer *server) runCodeChecks() {
 		thisServer.checkSpecs(clsName, boSpecs)
 	}
 
+	thisServer.checkTableNames()
+
 	slog.Info(fmt.Sprintf("done checking the code in %s", time.Since(start)))
 }
 
@@ -30,6 +34,11 @@ func (thisServer *server) checkSpecs(clsName className, boSpecs IBusinessObjectS
 		core.PanicMsg("The class name '%s' should be pascal-cased, i.e. %s", clsName, expected)
 	}
 
+	// checking how the class hierarchies are mapped
+	if boSpecs.base().inheritance != InheritanceStrategyNONE {
+		checkInheritance(clsName, boSpecs)
+	}
+
 	if !boSpecs.base().abstract {
 		// various check, whether there's persistence or not
 		for _, field := range boSpecs.base().fields {
@@ -50,6 +59,14 @@ func (thisServer *server) checkSpecs(clsName className, boSpecs IBusinessObjectS
 				core.PanicMsg("Class '%s' should be SetNotPersisted, SetAbstract, or associated with a DB", clsName)
 			}
 
+			// checking the table & column names, which might have been customised
+			checkSQLNames(clsName, boSpecs)
+
+			// checking the relationships loaded along with this class' BOs can actually be loaded
+			for _, loadingType := range core.GetSortedKeys(boSpecs.base().loadingTypes) {
+				checkLoadingScenarios(clsName, loadingType, boSpecs.base().loadingTypes[loadingType])
+			}
+
 			// checking the fields
 			for _, field := range boSpecs.base().fields {
 				switch field := field.(type) {
@@ -57,6 +74,10 @@ func (thisServer *server) checkSpecs(clsName className, boSpecs IBusinessObjectS
 					if field.name != "ID" && field.size == 0 && !field.isNotPersisted() {
 						core.PanicMsg("Field '%s.%s' should have a max size set, or be SetNotPersisted()", clsName, field.name)
 					}
+				case *EnumField:
+					if field.isStoredInChildTable() {
+						core.PanicMsg("Field '%s.%s' cannot be SetStoredInChildTable(), since multiple enum values are stored as a list", clsName, field.name)
+					}
 				}
 			}
 		}
@@ -76,6 +97,15 @@ func (thisServer *server) checkSpecs(clsName className, boSpecs IBusinessObjectS
 				if nbChildToParentRelationships > 1 {
 					core.PanicMsg("There cannot be more than one child to parent relationship in '%s'", clsName)
 				}
+
+				// a polymorphic relationship's column must be able to contain the IDs of all its targets
+				for _, target := range relationship.targets {
+					if relationship.polymorphic && target.getIDStrategy() != relationship.targets[0].getIDStrategy() {
+						core.PanicMsg("The targets of relationship '%s.%s' should all have the same ID strategy, "+
+							"which is not the case of '%s' and '%s'", clsName, relationship.name,
+							relationship.targets[0].base().name, target.base().name)
+					}
+				}
 			}
 		}
 	}
@@ -89,11 +119,155 @@ func (thisServer *server) checkSpecs(clsName className, boSpecs IBusinessObjectS
 	// TODO SOON: query BObj : prevent some property types
 	// TODO
 	// TODO	LATER: no column name on not-persisted links
-	// TODO LATER: allow custom table name
-	// TODO LATER: allow custom column name
 	// TODO LATER: tracking policy
-	// TODO LATER: unique table name per DB
-	// TODO LATER: unique column name per property
 	// TODO LATER: personal info asserted - with suggestions! (lastname, firstName, mail, email, phone, etc.)
 	// TODO LATER: confidential info asserted - with suggestions! (password, pass, passwd)
 }
+
+// checking that the relationships of the given loading type's scenarios point to a single persisted class, through a
+// column either on their owner's table or on their targets' one, and that the sub-scenarios are about the targets' relationships
+func checkLoadingScenarios(clsName className, loadingType LoadingType, scenarios []*LoadingScenario) {
+	for _, scenario := range scenarios {
+		relationship := scenario.relationship
+		target := relationship.targets[0]
+
+		if relationship.polymorphic {
+			core.PanicMsg("Loading type '%s' of class '%s': polymorphic relationship '%s' cannot be loaded",
+				loadingType, clsName, relationship.name)
+		}
+
+		if !target.base().isPersisted() {
+			core.PanicMsg("Loading type '%s' of class '%s': relationship '%s' cannot be loaded, since class '%s' is not persisted",
+				loadingType, clsName, relationship.name, target.base().name)
+		}
+
+		if !relationship.needsColumn() && (len(relationship.backRefs) == 0 || !relationship.backRefs[0].needsColumn()) {
+			core.PanicMsg("Loading type '%s' of class '%s': relationship '%s' cannot be loaded, since neither it nor its "+
+				"back ref is persisted in a column", loadingType, clsName, relationship.name)
+		}
+
+		for _, subScenario := range scenario.with {
+			if subScenario.relationship == nil || target.base().relationships[subScenario.relationship.name] != subScenario.relationship {
+				core.PanicMsg("Loading type '%s' of class '%s': the scenarios loaded with '%s' should be built with With(), "+
+					"on relationships of class '%s'", loadingType, clsName, relationship.name, target.base().name)
+			}
+		}
+
+		checkLoadingScenarios(clsName, loadingType, scenario.with)
+	}
+}
+
+// the names we accept for tables & columns, since they're used as is in the SQL queries
+var sqlNameREGEX = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// checking that the table & column names of the given persisted class are valid, and that no column is used twice
+func checkSQLNames(clsName className, boSpecs IBusinessObjectSpecs) {
+	if !sqlNameREGEX.MatchString(boSpecs.getTableName()) {
+		core.PanicMsg("Class '%s' has an invalid table name: '%s'", clsName, boSpecs.getTableName())
+	}
+
+	propertiesByColumn := map[string]string{}
+	for _, property := range boSpecs.base().getPersistedProperties() {
+		columnName := property.getColumnName()
+		if !sqlNameREGEX.MatchString(columnName) {
+			core.PanicMsg("Property '%s.%s' has an invalid column name: '%s'", clsName, property.getName(), columnName)
+		}
+
+		if otherProperty, exists := propertiesByColumn[strings.ToLower(columnName)]; exists {
+			core.PanicMsg("Properties '%s.%s' and '%s.%s' cannot both use column '%s'",
+				clsName, otherProperty, clsName, property.getName(), columnName)
+		}
+
+		propertiesByColumn[strings.ToLower(columnName)] = property.getName()
+	}
+}
+
+// checking that, within each DB, no table is used by 2 classes - or by a class and a multiple field's child table
+func (thisServer *server) checkTableNames() {
+	usagesByDB := map[*DB]map[string]string{}
+
+	for _, boSpecs := range core.GetSortedValues(specsRegistry.items) {
+		if boSpecs.base().abstract || !boSpecs.base().isPersisted() || boSpecs.getInDB() == nil {
+			continue
+		}
+
+		usages := usagesByDB[boSpecs.getInDB()]
+		if usages == nil {
+			usages = map[string]string{}
+			usagesByDB[boSpecs.getInDB()] = usages
+		}
+
+		// the class' own table, and the child tables of its multiple fields; in a single table hierarchy,
+		// these tables are shared by all the classes
+		user := "class '" + string(boSpecs.base().name) + "'"
+		if boSpecs.base().isInSingleTable() {
+			user = "the hierarchy of '" + string(boSpecs.base().getMappedHierarchyRoot().name) + "'"
+		}
+
+		tables := map[string]string{boSpecs.getTableName(): user}
+		for _, field := range boSpecs.base().getChildTableFields() {
+			tables[field.getChildTableName()] = "field '" + field.getName() + "' of " + user
+		}
+
+		for _, tableName := range core.GetSortedKeys(tables) {
+			if otherUsage, exists := usages[strings.ToLower(tableName)]; exists && otherUsage != tables[tableName] {
+				core.PanicMsg("Table '%s' is used by both %s and %s, in DB '%s'",
+					tableName, otherUsage, tables[tableName], boSpecs.getInDB().config.DbID)
+			}
+
+			usages[strings.ToLower(tableName)] = tables[tableName]
+		}
+	}
+}
+
+// checking that the given abstract class' inheritance strategy can be applied
+func checkInheritance(clsName className, boSpecs IBusinessObjectSpecs) {
+	if !boSpecs.base().abstract {
+		core.PanicMsg("Class '%s' should be SetAbstract() to have an inheritance strategy", clsName)
+	}
+
+	if root := boSpecs.base().getMappedHierarchyRoot(); root != nil {
+		core.PanicMsg("Class '%s' cannot have an inheritance strategy, since it inherits from '%s', which already has one", clsName, root.name)
+	}
+
+	if boSpecs.base().isNotPersisted() || boSpecs.getInDB() == nil {
+		core.PanicMsg("Class '%s' should be associated with a DB to have an inheritance strategy", clsName)
+	}
+
+	propertiesByColumn := map[string]string{}
+	for _, concrete := range boSpecs.base().getConcreteSubSpecs() {
+		if concrete.getInDB() != boSpecs.getInDB() {
+			core.PanicMsg("Class '%s' should be in the same DB as '%s', which it inherits from", concrete.base().name, clsName)
+		}
+
+		switch boSpecs.base().inheritance {
+		case InheritanceStrategySINGLExTABLE:
+			// all the rows share the same primary key
+			if concrete.getIDStrategy() != boSpecs.getIDStrategy() {
+				core.PanicMsg("Class '%s' should have the same ID strategy as '%s', since they share the same table",
+					concrete.base().name, clsName)
+			}
+			if concrete.ID().getColumnName() != boSpecs.ID().getColumnName() {
+				core.PanicMsg("Class '%s' should have the same ID column as '%s', since they share the same table",
+					concrete.base().name, clsName)
+			}
+
+			// 2 different properties cannot be stored in the same column
+			for _, property := range concrete.base().getPersistedProperties() {
+				if otherProperty, exists := propertiesByColumn[property.getColumnName()]; exists && otherProperty != property.getName() {
+					core.PanicMsg("Properties '%s' and '%s' cannot both use column '%s', in the single table hierarchy of '%s'",
+						otherProperty, property.getName(), property.getColumnName(), clsName)
+				}
+
+				propertiesByColumn[property.getColumnName()] = property.getName()
+			}
+
+		case InheritanceStrategyTABLExPERxCLASS:
+			// the BOs read from the various tables must not be mistaken for one another
+			if !concrete.getIDStrategy().isUUID() {
+				core.PanicMsg("Class '%s' should have a UUID ID strategy, since its IDs must be unique across the tables of '%s' hierarchy",
+					concrete.base().name, clsName)
+			}
+		}
+	}
+}
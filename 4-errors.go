@@ -24,3 +24,17 @@ func RecoverError(msg string, params ...interface{}) {
 		slog.Error(fmt.Sprintf(msg+". Cause: %v. Stack: \n%s", append(params, err, string(debug.Stack()))...))
 	}
 }
+
+// ------------------------------------------------------------------------------------------------
+// Specific errors
+// ------------------------------------------------------------------------------------------------
+
+// NotFoundError tells that no business object matches what's been looked for
+type NotFoundError struct {
+	ClassName string // the class of the business object looked for
+	Criteria  string // how it's been looked for, e.g. "'ID = 12'"
+}
+
+func (thisErr *NotFoundError) Error() string {
+	return "No '" + thisErr.ClassName + "' found with " + thisErr.Criteria
+}
@@ -0,0 +1,205 @@
+// ------------------------------------------------------------------------------------------------
+// Here we implement the data access instructions for the multiple values stored in child tables
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	core "github.com/aldesgroup/corego"
+)
+
+// the columns of any child values table
+var childValuesCOLUMNS = []string{childValuesOWNERxID, childValuesPOSITION, childValuesVALUE}
+
+// loads, for the given BOs, the values of all their multiple fields that are stored in child tables - or only the ones
+// of the operation's projection, if any
+func loadChildValues[ResourceType IBusinessObject](daoCtx DaoContext, op *daoOperation, bObjs []ResourceType) error {
+	childTableFields := op.boSpecs.base().getChildTableFields()
+	if len(childTableFields) == 0 || len(bObjs) == 0 {
+		return nil
+	}
+
+	// indexing the BOs by ID
+	bObjsByID := map[string]ResourceType{}
+	for _, bObj := range bObjs {
+		bObjsByID[string(bObj.GetID())] = bObj
+	}
+
+	for _, field := range childTableFields {
+		// not reading the values that are not asked for
+		if op.projection != nil && !op.projection[field.getName()] {
+			continue
+		}
+
+		// reading all the values, batch after batch, with their positions
+		valuesByOwner := map[string][]*childValue{}
+		ownerIDs := getIDsAsStrings(bObjs)
+		batchSize := op.db.adapter.getMaxParamsPerQuery()
+		for batchStart := 0; batchStart < len(ownerIDs); batchStart += batchSize {
+			batch := ownerIDs[batchStart:min(batchStart+batchSize, len(ownerIDs))]
+			if errRead := readChildValues(daoCtx, op, field, batch, valuesByOwner); errRead != nil {
+				return errRead
+			}
+		}
+
+		// setting the values, in the right order, on each BO
+		for ownerID, bObj := range bObjsByID {
+			values := valuesByOwner[ownerID]
+			slices.SortFunc(values, func(v1, v2 *childValue) int { return v1.position - v2.position })
+
+			if errSet := op.class.SetValueAsString(bObj, field.getName(), childValuesToString(field, values)); errSet != nil {
+				return ErrorC(errSet, "Could not set the values read from table '%s'", field.getChildTableName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// a value read from a child values table
+type childValue struct {
+	position int
+	value    string
+}
+
+// reads the values of the given field, for the given owners, into the given map
+func readChildValues(daoCtx DaoContext, op *daoOperation, field IField, ownerIDs []string, valuesByOwner map[string][]*childValue) error {
+	args := core.MapFn(ownerIDs, func(ownerID string) any { return op.toSQLArg(op.boSpecs.ID(), ownerID) })
+	query := op.db.adapter.getSelectQuery(field.getChildTableName(), childValuesCOLUMNS, childValuesOWNERxID, len(ownerIDs))
+
+	rows, errQuery := daoCtx.getExecutor(op.db).Query(query, args...)
+	if errQuery != nil {
+		return ErrorC(errQuery, "Could not load values from table '%s'", field.getChildTableName())
+	}
+
+	defer func() {
+		if errClose := rows.Close(); errClose != nil {
+			slog.Error(fmt.Sprintf("Error while closing the rows: %s", errClose))
+		}
+	}()
+
+	for rows.Next() {
+		var ownerID, value any
+		var position int
+		if errScan := rows.Scan(&ownerID, &position, &value); errScan != nil {
+			return ErrorC(errScan, "Could not read a row from table '%s'", field.getChildTableName())
+		}
+
+		ownerIDAsString := op.fromSQLValue(op.boSpecs.ID(), ownerID)
+		valuesByOwner[ownerIDAsString] = append(valuesByOwner[ownerIDAsString],
+			&childValue{position: position, value: op.fromSQLValue(field, value)})
+	}
+
+	if errRows := rows.Err(); errRows != nil {
+		return ErrorC(errRows, "Error while iterating over the rows of table '%s'", field.getChildTableName())
+	}
+
+	return nil
+}
+
+// the given values as a JSON array, as expected by the value mappers
+func childValuesToString(field IField, values []*childValue) string {
+	_, isString := field.(*StringField)
+
+	items := make([]any, len(values))
+	for i, value := range values {
+		// numbers & booleans are written as they are
+		items[i] = core.IfThenElse[any](isString, value.value, json.RawMessage(value.value))
+	}
+
+	valuesAsBytes, errMarshal := json.Marshal(items)
+	if errMarshal != nil {
+		panic(ErrorC(errMarshal, "Could not write the values of '%s' as JSON", field.getName()))
+	}
+
+	return string(valuesAsBytes)
+}
+
+// saves, for the given BOs, the values of all their multiple fields that are stored in child tables;
+// if replace is true, then the values already stored are removed first
+func saveChildValues[ResourceType IBusinessObject](daoCtx DaoContext, op *daoOperation, bObjs []ResourceType, replace bool) error {
+	childTableFields := op.boSpecs.base().getChildTableFields()
+	if len(childTableFields) == 0 || len(bObjs) == 0 {
+		return nil
+	}
+
+	if replace {
+		if errDelete := deleteChildValues(daoCtx, op, getIDsAsStrings(bObjs)); errDelete != nil {
+			return errDelete
+		}
+	}
+
+	for _, field := range childTableFields {
+		// gathering all the rows to insert
+		args := []any{}
+		for _, bObj := range bObjs {
+			values, errValues := stringToMultipleValues(field, op.class.GetValueAsString(bObj, field.getName()))
+			if errValues != nil {
+				return errValues
+			}
+
+			for position, value := range values {
+				args = append(args, op.toSQLArg(op.boSpecs.ID(), string(bObj.GetID())), position, op.toSQLArg(field, value))
+			}
+		}
+
+		// inserting them, batch after batch
+		batchSize := min(bulkMAXxROWS, op.db.adapter.getMaxParamsPerQuery()/len(childValuesCOLUMNS)) * len(childValuesCOLUMNS)
+		for batchStart := 0; batchStart < len(args); batchStart += batchSize {
+			batch := args[batchStart:min(batchStart+batchSize, len(args))]
+			query := op.db.adapter.getInsertValuesQuery(field.getChildTableName(), childValuesCOLUMNS, len(batch)/len(childValuesCOLUMNS))
+			if _, errExec := daoCtx.getExecutor(op.db).Exec(query, batch...); errExec != nil {
+				return ErrorC(errExec, "Could not insert values into table '%s'", field.getChildTableName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// removes all the values stored in child tables for the BOs having the given IDs
+func deleteChildValues(daoCtx DaoContext, op *daoOperation, ownerIDs []string) error {
+	batchSize := op.db.adapter.getMaxParamsPerQuery()
+	for _, field := range op.boSpecs.base().getChildTableFields() {
+		for batchStart := 0; batchStart < len(ownerIDs); batchStart += batchSize {
+			batch := ownerIDs[batchStart:min(batchStart+batchSize, len(ownerIDs))]
+			args := core.MapFn(batch, func(ownerID string) any { return op.toSQLArg(op.boSpecs.ID(), ownerID) })
+			query := op.db.adapter.getDeleteQuery(field.getChildTableName(), childValuesOWNERxID, len(batch))
+			if _, errExec := daoCtx.getExecutor(op.db).Exec(query, args...); errExec != nil {
+				return ErrorC(errExec, "Could not delete values from table '%s'", field.getChildTableName())
+			}
+		}
+	}
+
+	return nil
+}
+
+// reads the JSON array given by the value mappers, and returns its items as strings
+func stringToMultipleValues(field IField, valueAsString string) ([]string, error) {
+	if valueAsString == "" || valueAsString == "null" {
+		return nil, nil
+	}
+
+	items := []any{}
+	decoder := json.NewDecoder(strings.NewReader(valueAsString))
+	decoder.UseNumber()
+	if errDecode := decoder.Decode(&items); errDecode != nil {
+		return nil, ErrorC(errDecode, "Could not read the values of '%s' as a JSON array", field.getName())
+	}
+
+	return core.MapFn(items, func(item any) string {
+		switch item := item.(type) {
+		case string:
+			return item
+		case json.Number:
+			return item.String()
+		default:
+			return fmt.Sprintf("%v", item)
+		}
+	}), nil
+}
@@ -4,69 +4,726 @@
 // ------------------------------------------------------------------------------------------------
 package goald
 
-// var mockDatabase map[string]IBusinessObject = map[string]IBusinessObject{}
+import (
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
-func dbInsert(_ DaoContext, bObj IBusinessObject) error {
-	// uuid, errUuid := uuid.NewRandom()
-	// if errUuid != nil {
-	// 	return ErrorC(errUuid, "could not generate a new UUID")
-	// }
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
+)
 
-	// bObj.setID(uuid.String())
+// ------------------------------------------------------------------------------------------------
+// Operations on 1 BO
+// ------------------------------------------------------------------------------------------------
+
+// inserts the given BO, which must have no ID yet, and sets its new ID
+func dbInsert(daoCtx DaoContext, boSpecs IBusinessObjectSpecs, bObj IBusinessObject) error {
+	return dbInsertMany(daoCtx, boSpecs, []IBusinessObject{bObj})
+}
+
+// loads all the persisted BOs of the given class - with only the given properties, if any, along with their ID
+func dbLoadList[ResourceType IBusinessObject](daoCtx DaoContext, boSpecs IBusinessObjectSpecs, propertyNames ...string) (result []ResourceType, err error) {
+	return loadFromEachTable(boSpecs, func(boSpecs IBusinessObjectSpecs) ([]ResourceType, error) {
+		op, errOp := newDaoOperation(boSpecs, true)
+		if errOp != nil {
+			return nil, errOp
+		}
+		op.project(propertyNames)
+
+		return loadBOs[ResourceType](daoCtx, op, op.db.adapter.getSelectQuery(op.source, op.columns, "", 0))
+	})
+}
+
+// loads the BO of the class owning the given property, for which this property has the given value - with only
+// the given properties, if any, along with its ID
+func dbLoadOne(daoCtx DaoContext, idProp IField, idPropVal string, propertyNames ...string) (result IBusinessObject, err error) {
+	loadedBOs, errLoad := dbLoadMany[IBusinessObject](daoCtx, idProp, []string{idPropVal}, propertyNames...)
+	if errLoad != nil {
+		return nil, errLoad
+	}
+
+	if len(loadedBOs) == 0 {
+		return nil, &NotFoundError{ClassName: string(idProp.ownerSpecs().base().name), Criteria: fmt.Sprintf("'%s = %s'", idProp.getName(), idPropVal)}
+	}
+
+	return loadedBOs[0], nil
+}
+
+// loads the BOs of the class owning the given property - field or relationship with a column - for which this
+// property has one of the given values - with only the given properties, if any, along with their ID
+func dbLoadMany[ResourceType IBusinessObject](daoCtx DaoContext, prop iBusinessObjectProperty, propVals []string,
+	propertyNames ...string) (result []ResourceType, err error) {
+	return loadFromEachTable(prop.ownerSpecs(), func(boSpecs IBusinessObjectSpecs) ([]ResourceType, error) {
+		return dbLoadManyFrom[ResourceType](daoCtx, boSpecs, prop, propVals, propertyNames...)
+	})
+}
+
+// loads the BOs of the given class, for which the given property has one of the given values
+func dbLoadManyFrom[ResourceType IBusinessObject](daoCtx DaoContext, boSpecs IBusinessObjectSpecs, prop iBusinessObjectProperty,
+	propVals []string, propertyNames ...string) (result []ResourceType, err error) {
+	op, errOp := newDaoOperation(boSpecs, true)
+	if errOp != nil {
+		return nil, errOp
+	}
+	op.project(propertyNames)
+
+	// not exceeding the max number of params per query
+	batchSize := op.db.adapter.getMaxParamsPerQuery()
+	for batchStart := 0; batchStart < len(propVals); batchStart += batchSize {
+		batch := propVals[batchStart:min(batchStart+batchSize, len(propVals))]
+		args := make([]any, len(batch))
+		for i, propVal := range batch {
+			args[i] = op.toSQLArg(prop, propVal)
+		}
+
+		query := op.db.adapter.getSelectQuery(op.source, op.columns, prop.getColumnName(), len(batch))
+		loadedBOs, errLoad := loadBOs[ResourceType](daoCtx, op, query, args...)
+		if errLoad != nil {
+			return nil, errLoad
+		}
+
+		result = append(result, loadedBOs...)
+	}
+
+	return result, nil
+}
+
+// loads the BOs of the class owning the given JSON field, for which the value found at the given path
+// within the JSON documents - e.g. "$.address.city" - is the given one
+func dbLoadByJSONPath[ResourceType IBusinessObject](daoCtx DaoContext, jsonField *JSONField, path string, value string) ([]ResourceType, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, Error("Invalid JSON path '%s': it should start with '$'", path)
+	}
+
+	return loadFromEachTable(jsonField.ownerSpecs(), func(boSpecs IBusinessObjectSpecs) ([]ResourceType, error) {
+		op, errOp := newDaoOperation(boSpecs, true)
+		if errOp != nil {
+			return nil, errOp
+		}
+
+		return loadBOs[ResourceType](daoCtx, op, op.db.adapter.getJSONPathSelectQuery(op.source, op.columns, jsonField.getColumnName()), path, value)
+	})
+}
+
+// runs the given loading function for the given class - or, if it's an abstract class mapped with 1 table
+// per concrete class, for each of these concrete classes - and returns all the loaded BOs
+func loadFromEachTable[ResourceType IBusinessObject](boSpecs IBusinessObjectSpecs,
+	load func(boSpecs IBusinessObjectSpecs) ([]ResourceType, error)) ([]ResourceType, error) {
+	concreteSpecs := boSpecs.base().getTablePerClassSpecs()
+	if concreteSpecs == nil {
+		return load(boSpecs)
+	}
+
+	result := []ResourceType{}
+	for _, concrete := range concreteSpecs {
+		loadedBOs, errLoad := load(concrete)
+		if errLoad != nil {
+			return nil, errLoad
+		}
+
+		result = append(result, loadedBOs...)
+	}
+
+	return result, nil
+}
+
+// updates all the persisted properties of the given BO, which must have an ID
+func dbUpdate(daoCtx DaoContext, boSpecs IBusinessObjectSpecs, input IBusinessObject) error {
+	return dbUpdateMany(daoCtx, boSpecs, []IBusinessObject{input})
+}
+
+// updates only the persisted properties of the given BO whose values differ from the ones of the given previous state,
+// so that the columns untouched by a patch are not overwritten
+func dbUpdateChanges(daoCtx DaoContext, boSpecs IBusinessObjectSpecs, bObj, previous IBusinessObject) error {
+	op, errOp := newWritingDaoOperation(boSpecs, true)
+	if errOp != nil {
+		return errOp
+	}
+
+	// keeping the ID, and the properties that have changed
+	changedProperties := []iBusinessObjectProperty{}
+	for _, property := range op.properties[1:] {
+		if op.class.GetValueAsString(bObj, property.getName()) != op.class.GetValueAsString(previous, property.getName()) {
+			changedProperties = append(changedProperties, property)
+		}
+	}
+
+	changedChildTableFields := []IField{}
+	for _, field := range boSpecs.base().getChildTableFields() {
+		if op.class.GetValueAsString(bObj, field.getName()) != op.class.GetValueAsString(previous, field.getName()) {
+			changedChildTableFields = append(changedChildTableFields, field)
+		}
+	}
+
+	op.properties = append(op.properties[:1:1], changedProperties...)
+	op.columns = core.MapFn(op.properties, func(property iBusinessObjectProperty) string { return property.getColumnName() })
+	if op.discriminator != "" {
+		op.columns = append(op.columns, discriminatorCOLUMN)
+	}
 
-	// mockDatabase[(bObj.GetID())] = bObj
+	return daoCtx.inTransaction(op.db, func() error {
+		if len(changedProperties) > 0 {
+			if _, errBulk := runBulkOperation(daoCtx, op, []IBusinessObject{bObj}, func(nbRows int) string {
+				return op.db.adapter.getUpdateManyQuery(op.tableName, op.columns, nbRows)
+			}); errBulk != nil {
+				return errBulk
+			}
+		}
+
+		// the values stored in child tables are replaced altogether
+		if len(changedChildTableFields) > 0 {
+			return saveChildValues(daoCtx, op, []IBusinessObject{bObj}, true)
+		}
+
+		return nil
+	})
+}
+
+// locks the BO for which the given property has the given value, so that no one else can write it until the end of
+// the transaction opened through the given context - which is required
+func dbLockOne(daoCtx DaoContext, idProp IField, idPropVal string) error {
+	op, errOp := newWritingDaoOperation(idProp.ownerSpecs(), true)
+	if errOp != nil {
+		return errOp
+	}
+
+	executor := daoCtx.getExecutor(op.db)
+	if executor == dbExecutor(op.db) {
+		return Error("Cannot lock a '%s' outside of a transaction", idProp.ownerSpecs().base().name)
+	}
+
+	rows, errQuery := executor.Query(op.db.adapter.getLockQuery(op.tableName, idProp.getColumnName()), op.toSQLArg(idProp, idPropVal))
+	if errQuery != nil {
+		return ErrorC(errQuery, "Could not lock the '%s' with '%s = %s'", idProp.ownerSpecs().base().name, idProp.getName(), idPropVal)
+	}
+
+	if errClose := rows.Close(); errClose != nil {
+		return ErrorC(errClose, "Could not lock the '%s' with '%s = %s'", idProp.ownerSpecs().base().name, idProp.getName(), idPropVal)
+	}
 
 	return nil
 }
 
-// func dbLoadList(_ DaoContext, boSpecs IBusinessObjectSpecs) (result []IBusinessObject, err error) {
-// 	for _, bObj := range mockDatabase {
-// 		if boSpecs == bObj.Class() {
-// 			result = append(result, bObj)
-// 		}
-// 	}
+// removes the BO whose ID is given
+func dbRemoveOne(daoCtx DaoContext, boSpecs IBusinessObjectSpecs, id BObjID) error {
+	op, errOp := newWritingDaoOperation(boSpecs, true)
+	if errOp != nil {
+		return errOp
+	}
+
+	return daoCtx.inTransaction(op.db, func() error {
+		result, errExec := daoCtx.getExecutor(op.db).Exec(op.db.adapter.getDeleteQuery(op.tableName, boSpecs.ID().getColumnName(), 1), op.toSQLArg(boSpecs.ID(), string(id)))
+		if errExec != nil {
+			return ErrorC(errExec, "Could not delete the '%s' with ID %s", boSpecs.base().name, id)
+		}
+
+		if nbRows, errRows := result.RowsAffected(); errRows == nil && nbRows == 0 {
+			return &NotFoundError{ClassName: string(boSpecs.base().name), Criteria: "ID " + string(id)}
+		}
+
+		return deleteChildValues(daoCtx, op, []string{string(id)})
+	})
+}
+
+// ------------------------------------------------------------------------------------------------
+// Bulk operations, i.e. on many BOs of the same class at once
+// ------------------------------------------------------------------------------------------------
+
+// inserts all the given BOs, which must have no ID yet, and sets their new IDs
+func dbInsertMany[ResourceType IBusinessObject](daoCtx DaoContext, boSpecs IBusinessObjectSpecs, bObjs []ResourceType) error {
+	// if the IDs are not generated by the DB, then they're inserted like any other column
+	generatedIDs := boSpecs.getIDStrategy().isUUID()
+
+	op, errOp := newWritingDaoOperation(boSpecs, generatedIDs)
+	if errOp != nil {
+		return errOp
+	}
+
+	if generatedIDs {
+		setNewIDs(boSpecs, bObjs)
+	}
 
-// 	return
-// }
+	errRun := daoCtx.inTransaction(op.db, func() error {
+		if _, errBulk := runBulkOperation(daoCtx, op, bObjs, func(nbRows int) string {
+			return op.db.adapter.getInsertManyQuery(op.tableName, boSpecs.ID().getColumnName(), op.columns, nbRows)
+		}); errBulk != nil {
+			return errBulk
+		}
 
-func dbLoadList[ResourceType IBusinessObject](_ DaoContext, boSpecs IBusinessObjectSpecs) (result []ResourceType, err error) {
-	class := getClass(boSpecs)
+		return saveChildValues(daoCtx, op, bObjs, false)
+	})
 
-	println(class)
+	// the BOs have not been inserted after all
+	if errRun != nil && generatedIDs {
+		resetIDs(bObjs)
+	}
 
-	return
+	return errRun
 }
 
-func dbLoadOne(_ DaoContext, idProp IField, idPropVal string) (result IBusinessObject, err error) {
-	// for _, bObj := range mockDatabase {
-	// 	if idProp.ownerSpecs() == bObj.Class() && idPropVal == bObj.GetValueAsString(idPropVal) {
-	// 		return bObj, nil
-	// 	}
-	// }
+// removes all the BOs whose IDs are given, which must all exist
+func dbRemoveMany(daoCtx DaoContext, boSpecs IBusinessObjectSpecs, ids []string) error {
+	op, errOp := newWritingDaoOperation(boSpecs, true)
+	if errOp != nil {
+		return errOp
+	}
 
-	return nil, Error("No '%s' found with '%s = %s'", idProp.ownerSpecs().base().name, idProp.getName(), idPropVal)
+	return daoCtx.inTransaction(op.db, func() error {
+		batchSize := op.db.adapter.getMaxParamsPerQuery()
+		for batchStart := 0; batchStart < len(ids); batchStart += batchSize {
+			batch := ids[batchStart:min(batchStart+batchSize, len(ids))]
+			args := core.MapFn(batch, func(id string) any { return op.toSQLArg(boSpecs.ID(), id) })
+
+			result, errExec := daoCtx.getExecutor(op.db).Exec(op.db.adapter.getDeleteQuery(op.tableName, boSpecs.ID().getColumnName(), len(batch)), args...)
+			if errExec != nil {
+				return ErrorC(errExec, "Could not delete the '%s' for rows %d to %d", boSpecs.base().name, batchStart, batchStart+len(batch)-1)
+			}
+
+			if nbRows, errRows := result.RowsAffected(); errRows == nil && nbRows != int64(len(batch)) {
+				return Error("Only %d '%s' found out of the %d to delete, for rows %d to %d",
+					nbRows, boSpecs.base().name, len(batch), batchStart, batchStart+len(batch)-1)
+			}
+		}
+
+		return deleteChildValues(daoCtx, op, ids)
+	})
+}
+
+// updates all the given BOs, which must all have an ID
+func dbUpdateMany[ResourceType IBusinessObject](daoCtx DaoContext, boSpecs IBusinessObjectSpecs, bObjs []ResourceType) error {
+	op, errOp := newWritingDaoOperation(boSpecs, true)
+	if errOp != nil {
+		return errOp
+	}
+
+	return daoCtx.inTransaction(op.db, func() error {
+		if _, errBulk := runBulkOperation(daoCtx, op, bObjs, func(nbRows int) string {
+			return op.db.adapter.getUpdateManyQuery(op.tableName, op.columns, nbRows)
+		}); errBulk != nil {
+			return errBulk
+		}
+
+		return saveChildValues(daoCtx, op, bObjs, true)
+	})
 }
 
-func dbRemoveOne(_ DaoContext, idProp IField, idPropVal string) (result IBusinessObject, err error) {
-	// for _, bObj := range mockDatabase {
-	// 	if idProp.ownerSpecs() == bObj.Class() && idPropVal == bObj.GetValueAsString(idPropVal) {
-	// 		delete(mockDatabase, string(bObj.GetID()))
-	// 		return bObj, nil
-	// 	}
-	// }
+// inserts or updates the given BOs, depending on an existing row having the same value for the given key field;
+// all the BOs get their ID set, and we return, for each of them, true if it's been inserted
+func dbUpsertMany[ResourceType IBusinessObject](daoCtx DaoContext, keyField IField, bObjs []ResourceType) ([]bool, error) {
+	// if the IDs are not generated by the DB, then they're inserted like any other column - but never updated
+	boSpecs := keyField.ownerSpecs()
+	generatedIDs := boSpecs.getIDStrategy().isUUID()
+
+	op, errOp := newWritingDaoOperation(boSpecs, generatedIDs)
+	if errOp != nil {
+		return nil, errOp
+	}
 
-	return nil, Error("No '%s' found with '%s = %s'", idProp.ownerSpecs().base().name, idProp.getName(), idPropVal)
+	// the IDs of the BOs that are going to be updated instead of inserted are overwritten by the query output
+	if generatedIDs {
+		setNewIDs(boSpecs, bObjs)
+	}
+
+	var inserted []bool
+	errRun := daoCtx.inTransaction(op.db, func() (errBulk error) {
+		if inserted, errBulk = runBulkOperation(daoCtx, op, bObjs, func(nbRows int) string {
+			return op.db.adapter.getUpsertManyQuery(op.tableName, boSpecs.ID().getColumnName(), keyField.getColumnName(), op.columns, nbRows)
+		}); errBulk != nil {
+			return errBulk
+		}
+
+		return saveChildValues(daoCtx, op, bObjs, true)
+	})
+
+	if errRun != nil && generatedIDs {
+		resetIDs(bObjs)
+	}
+
+	return inserted, errRun
 }
 
-func dbUpdate(_ DaoContext, input IBusinessObject) error {
-	// instore := mockDatabase[string(input.GetID())]
-	// if instore == nil {
-	// 	return Error("No object exists with ID %s", input.GetID())
-	// }
+// ------------------------------------------------------------------------------------------------
+// Utilities
+// ------------------------------------------------------------------------------------------------
 
-	// input.setClassName(instore.getClassName())
+// what's needed to run 1 operation on the table of 1 BO class
+type daoOperation struct {
+	db            *DB
+	boSpecs       IBusinessObjectSpecs
+	class         IClass
+	tableName     string
+	properties    []iBusinessObjectProperty // the properties for which we pass or read values, in the order of the columns
+	columns       []string                  // the corresponding columns
+	batchSize     int                       // the max number of rows handled with 1 query
+	source        string                    // where the rows are read from: the table, or the part of it containing this class' BOs
+	discriminator string                    // in a single table hierarchy, the class name written into the discriminator column
+	polymorphic   bool                      // if true, the rows read can be BOs of several concrete classes, told apart by the discriminator column
+	projection    map[string]bool           // if set, only the properties with these names are read, along with the ID
+}
 
-	// mockDatabase[string(input.GetID())] = input
+// the number of rows we allow in 1 query, whatever the adapter limitations on the number of parameters
+const bulkMAXxROWS = 1000
+
+// returns an operation to read the BOs of the given class
+func newDaoOperation(boSpecs IBusinessObjectSpecs, withID bool) (*daoOperation, error) {
+	return buildDaoOperation(boSpecs, withID, false)
+}
+
+// returns an operation to write into the class' table, which must not be read-only
+func newWritingDaoOperation(boSpecs IBusinessObjectSpecs, withID bool) (*daoOperation, error) {
+	if boSpecs.isReadOnly() {
+		return nil, Error("Class '%s' is read-only, its BOs cannot be created, updated or deleted", boSpecs.base().name)
+	}
+
+	if boSpecs.base().abstract {
+		return nil, Error("Class '%s' is abstract, its BOs cannot be created, updated or deleted", boSpecs.base().name)
+	}
+
+	return buildDaoOperation(boSpecs, withID, true)
+}
+
+func buildDaoOperation(boSpecs IBusinessObjectSpecs, withID bool, writing bool) (*daoOperation, error) {
+	if !boSpecs.base().isPersisted() {
+		return nil, Error("Class '%s' is not persisted", boSpecs.base().name)
+	}
+
+	db := boSpecs.getInDB()
+	if db == nil || db.DB == nil {
+		return nil, Error("Class '%s' is not associated with an initialised DB", boSpecs.base().name)
+	}
+
+	op := &daoOperation{
+		db:        db,
+		boSpecs:   boSpecs,
+		class:     getClass(boSpecs),
+		tableName: boSpecs.getTableName(),
+		source:    boSpecs.getTableName(),
+	}
+
+	// the ID always comes first in the persisted properties
+	op.properties = boSpecs.base().getPersistedProperties()
+
+	// in a single table hierarchy, we can read BOs of all the concrete classes inheriting from this one,
+	// but only from the rows of these classes
+	if boSpecs.base().isInSingleTable() && !writing {
+		concreteSpecs := boSpecs.base().getConcreteSubSpecs()
+		op.properties = getUnionOfPersistedProperties(append([]IBusinessObjectSpecs{boSpecs}, concreteSpecs...))
+		op.polymorphic = true
+		op.source = db.adapter.getFilteredTableSource(op.tableName, discriminatorCOLUMN,
+			core.MapFn(concreteSpecs, func(concrete IBusinessObjectSpecs) string { return string(concrete.base().name) }))
+	}
+
+	if !withID {
+		op.properties = op.properties[1:]
+	}
+
+	for _, property := range op.properties {
+		op.columns = append(op.columns, property.getColumnName())
+	}
+
+	// the discriminator column always comes last
+	if boSpecs.base().isInSingleTable() {
+		op.columns = append(op.columns, discriminatorCOLUMN)
+		if writing {
+			op.discriminator = string(boSpecs.base().name)
+		}
+	}
+
+	op.batchSize = min(bulkMAXxROWS, db.adapter.getMaxParamsPerQuery()/max(1, len(op.columns)))
+
+	return op, nil
+}
+
+// restricts the properties read by this operation to the ones with the given names, along with the ID - and the
+// version & last modification date, which the conditional requests rely on; if no name is given, then all the
+// properties are read
+func (op *daoOperation) project(propertyNames []string) {
+	if len(propertyNames) == 0 {
+		return
+	}
+
+	op.projection = map[string]bool{op.boSpecs.ID().getName(): true}
+	if versionField := op.boSpecs.base().versionField; versionField != nil {
+		op.projection[versionField.getName()] = true
+	}
+	if lastModifiedField := op.boSpecs.base().lastModifiedField; lastModifiedField != nil {
+		op.projection[lastModifiedField.getName()] = true
+	}
+	for _, propertyName := range propertyNames {
+		op.projection[propertyName] = true
+	}
+
+	properties := []iBusinessObjectProperty{}
+	columns := []string{}
+	for i, property := range op.properties {
+		if op.projection[property.getName()] {
+			properties = append(properties, property)
+			columns = append(columns, op.columns[i])
+		}
+	}
+
+	// the discriminator column, if any, always comes last
+	op.columns = append(columns, op.columns[len(op.properties):]...)
+	op.properties = properties
+}
+
+// converts the string value of a BO property into an arg that can be passed to an SQL query
+func (op *daoOperation) toSQLArg(property iBusinessObjectProperty, valueAsString string) any {
+	switch property.(type) {
+	case *StringField:
+		// an empty optional string is stored as NULL, so that it does not collide with another one in a unique column
+		if valueAsString == "" && !property.isMandatory() {
+			return nil
+		}
+		return valueAsString
+	case *DateField:
+		if valueAsString == "" {
+			return nil
+		}
+		return *core.StringToDate(valueAsString, op.tableName+"."+property.getColumnName())
+	case *BinaryField:
+		if valueAsString == "" {
+			return nil
+		}
+		value, errDecode := base64.StdEncoding.DecodeString(valueAsString)
+		core.PanicMsgIfErr(errDecode, "Could not decode the binary value for column '%s.%s'", op.tableName, property.getColumnName())
+		return value
+	default:
+		if valueAsString == "" {
+			return nil
+		}
+		return valueAsString
+	}
+}
+
+// converts a value read from the DB into the string value of a BO property
+func (op *daoOperation) fromSQLValue(property iBusinessObjectProperty, value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		// raw binary data are handled as base64 strings by the value mappers
+		if _, isBinary := property.(*BinaryField); isBinary {
+			return base64.StdEncoding.EncodeToString(value)
+		}
+		return string(value)
+	case int64:
+		return core.Int64ToString(value)
+	case float64:
+		return core.Float64ToString(value)
+	case bool:
+		return core.BoolToString(value)
+	case time.Time:
+		switch property.(type) {
+		case *DateOnlyField:
+			return value.Format(utils.DateOnlyLAYOUT)
+		case *TimeOfDayField:
+			return value.Format(utils.TimeOfDayLAYOUT)
+		}
+		return core.DateToString(&value)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// runs the given select query, and returns the BOs built from the rows, along with their values stored in child tables
+func loadBOs[ResourceType IBusinessObject](daoCtx DaoContext, op *daoOperation, query string, args ...any) ([]ResourceType, error) {
+	result, errRead := readBOs[ResourceType](daoCtx, op, query, args...)
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	// the BOs read from a single table hierarchy can have various classes, with various multiple fields
+	if op.polymorphic {
+		return result, loadChildValuesByClass(daoCtx, result, op.projection)
+	}
+
+	if errLoad := loadChildValues(daoCtx, op, result); errLoad != nil {
+		return nil, errLoad
+	}
+
+	return result, nil
+}
+
+// loads the values stored in child tables, for BOs of various classes - only for the given properties, if any
+func loadChildValuesByClass[ResourceType IBusinessObject](daoCtx DaoContext, bObjs []ResourceType, projection map[string]bool) error {
+	bObjsByClass := map[className][]ResourceType{}
+	for _, bObj := range bObjs {
+		bObjsByClass[bObj.getClassName()] = append(bObjsByClass[bObj.getClassName()], bObj)
+	}
+
+	for clsName, classBObjs := range bObjsByClass {
+		// an operation on this very class, not a polymorphic one
+		classOp, errOp := buildDaoOperation(specsForName(clsName), true, true)
+		if errOp != nil {
+			return errOp
+		}
+		classOp.projection = projection
+
+		if errLoad := loadChildValues(daoCtx, classOp, classBObjs); errLoad != nil {
+			return errLoad
+		}
+	}
 
 	return nil
 }
+
+// returns true if the given property - possibly inherited - is persisted for the given class
+func hasPersistedProperty(clsName className, property iBusinessObjectProperty) bool {
+	return slices.ContainsFunc(specsForName(clsName).base().getPersistedProperties(), func(classProperty iBusinessObjectProperty) bool {
+		return classProperty.getName() == property.getName()
+	})
+}
+
+// runs the given select query, and returns the BOs built from the rows
+func readBOs[ResourceType IBusinessObject](daoCtx DaoContext, op *daoOperation, query string, args ...any) ([]ResourceType, error) {
+	rows, errQuery := daoCtx.getExecutor(op.db).Query(query, args...)
+	if errQuery != nil {
+		return nil, ErrorC(errQuery, "Could not load objects from table '%s'", op.tableName)
+	}
+
+	defer func() {
+		if errClose := rows.Close(); errClose != nil {
+			slog.Error(fmt.Sprintf("Error while closing the rows: %s", errClose))
+		}
+	}()
+
+	// we're scanning the values as they come, the value mappers do the rest
+	values := make([]any, len(op.columns))
+	pointers := make([]any, len(op.columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	result := []ResourceType{}
+	for rows.Next() {
+		if errScan := rows.Scan(pointers...); errScan != nil {
+			return nil, ErrorC(errScan, "Could not read a row from table '%s'", op.tableName)
+		}
+
+		// the class of the BO to build
+		class := op.class
+		if op.polymorphic {
+			discriminator := op.fromSQLValue(nil, values[len(values)-1])
+			if class = classRegistry.items[className(discriminator)]; class == nil {
+				return nil, Error("Unknown class '%s' found in table '%s'", discriminator, op.tableName)
+			}
+		}
+
+		bObj, isResource := class.NewObject().(ResourceType)
+		if !isResource {
+			return nil, Error("Cannot load a '%s' from table '%s' as a %T; the BOs of a class hierarchy must be loaded as IBusinessObject",
+				class.getClassName(), op.tableName, bObj)
+		}
+		bObj.setClassName(class.getClassName())
+		for i, property := range op.properties {
+			// only setting the properties of the BO's class, when the row can contain other classes' properties
+			if op.polymorphic && !hasPersistedProperty(class.getClassName(), property) {
+				continue
+			}
+
+			if errSet := class.SetValueAsString(bObj, property.getName(), op.fromSQLValue(property, values[i])); errSet != nil {
+				return nil, ErrorC(errSet, "Could not read column '%s.%s'", op.tableName, op.columns[i])
+			}
+		}
+
+		result = append(result, bObj)
+	}
+
+	if errRows := rows.Err(); errRows != nil {
+		return nil, ErrorC(errRows, "Error while iterating over the rows of table '%s'", op.tableName)
+	}
+
+	return result, nil
+}
+
+// runs the given query builder over batches of the given BOs, within 1 transaction, and returns,
+// for each BO, true if it's been inserted; the BOs' IDs are set from the queries' output, if any
+func runBulkOperation[ResourceType IBusinessObject](daoCtx DaoContext, op *daoOperation, bObjs []ResourceType,
+	buildQuery func(nbRows int) string) (inserted []bool, err error) {
+	inserted = make([]bool, len(bObjs))
+
+	errTx := daoCtx.inTransaction(op.db, func() error {
+		for batchStart := 0; batchStart < len(bObjs); batchStart += op.batchSize {
+			batch := bObjs[batchStart:min(batchStart+op.batchSize, len(bObjs))]
+
+			// gathering the values for all the rows of the current batch
+			args := make([]any, 0, len(batch)*len(op.properties))
+			for _, bObj := range batch {
+				for _, property := range op.properties {
+					args = append(args, op.toSQLArg(property, op.class.GetValueAsString(bObj, property.getName())))
+				}
+				if op.discriminator != "" {
+					args = append(args, op.discriminator)
+				}
+			}
+
+			if errBatch := runBulkBatch(daoCtx.getExecutor(op.db), buildQuery(len(batch)), args, batch, inserted[batchStart:]); errBatch != nil {
+				return ErrorC(errBatch, "Could not run a bulk operation on table '%s' for rows %d to %d",
+					op.tableName, batchStart, batchStart+len(batch)-1)
+			}
+		}
+
+		return nil
+	})
+
+	if errTx != nil {
+		return nil, errTx
+	}
+
+	return inserted, nil
+}
+
+// runs 1 query and reads its output - if any - made of rows like: action, row number, ID
+func runBulkBatch[ResourceType IBusinessObject](executor dbExecutor, query string, args []any, batch []ResourceType, inserted []bool) error {
+	rows, errQuery := executor.Query(query, args...)
+	if errQuery != nil {
+		return errQuery
+	}
+
+	defer func() {
+		if errClose := rows.Close(); errClose != nil {
+			slog.Error(fmt.Sprintf("Error while closing the rows: %s", errClose))
+		}
+	}()
+
+	for rows.Next() {
+		var action, id string
+		var rowNum int
+		if errScan := rows.Scan(&action, &rowNum, &id); errScan != nil {
+			return ErrorC(errScan, "Could not read the output of the bulk operation")
+		}
+
+		batch[rowNum].setID(BObjID(id))
+		inserted[rowNum] = action == "INSERT"
+	}
+
+	return rows.Err()
+}
+
+// returns the string value of the BOs' IDs
+func getIDsAsStrings[ResourceType IBusinessObject](bObjs []ResourceType) []string {
+	return core.MapFn(bObjs, func(bObj ResourceType) string { return string(bObj.GetID()) })
+}
+
+// gives a new ID to all the given BOs that do not have one yet
+func setNewIDs[ResourceType IBusinessObject](boSpecs IBusinessObjectSpecs, bObjs []ResourceType) {
+	for _, bObj := range bObjs {
+		if bObj.GetID() == "" {
+			bObj.setID(boSpecs.base().newID())
+		}
+	}
+}
+
+// removes the IDs of the given BOs
+func resetIDs[ResourceType IBusinessObject](bObjs []ResourceType) {
+	for _, bObj := range bObjs {
+		bObj.setID("")
+	}
+}
@@ -4,6 +4,12 @@
 // ------------------------------------------------------------------------------------------------
 package goald
 
+import (
+	"strconv"
+
+	core "github.com/aldesgroup/corego"
+)
+
 // Controls, DB-inserts, post-treats the given BO
 // TODO - quick & dirty implem for now
 // func CreateBO[BOTYPE IBusinessObject](bloCtx BloContext, bObj *BOTYPE) error {
@@ -12,86 +18,555 @@ func CreateBO(bloCtx BloContext, bObj IBusinessObject) error {
 		return nil
 	}
 
-	// the business object should not have an ID already
-	if bObj.GetID() != 0 {
-		return Error("Could not create object since it already has an ID (%d)", bObj.GetID())
+	boSpecs := specsOf(bObj)
+	if boSpecs == nil {
+		return Error("Could not create object since no specs are registered for its class (%T)", bObj)
 	}
 
-	// do we have stuff to perform on the __BOBJ__ before inserting it ?
-	if err := bObj.ChangeBeforeInsert(bloCtx); err != nil {
-		return ErrorC(err, "Could not create object since the pre-insert got an error")
+	// the business object should not have an ID already
+	if bObj.GetID() != "" {
+		return Error("Could not create object since it already has an ID (%s)", bObj.GetID())
 	}
 
-	// check of "functional / business" validity
-	if err := bObj.IsValid(bloCtx); err != nil {
-		return ErrorC(err, "Could not create object since it is not valid")
-	}
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
 
-	// setting some tracking info
-	// bObj.SetCreatedByID(biContext.GetCurrentUser().GetID())
-	// bObj.SetCreatedBy(biContext.GetCurrentUser().GetLabel())
-	// bObj.SetCreation(core.Now())
-	// bObj.Set__BOBJ__Status(__BOBJ__StatusCREATED)
+	return daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// do we have stuff to perform on the __BOBJ__ before inserting it ?
+		if err := bObj.ChangeBeforeInsert(bloCtx); err != nil {
+			return ErrorC(err, "Could not create object since the pre-insert got an error")
+		}
 
-	// pushing to the DB ! We're going to add a new line within the __BOBJ__'s table
-	if err := dbInsert(bloCtx.GetDaoContext(), bObj); err != nil {
-		return ErrorC(err, "Could not create object because of a problem with the DB")
-	}
+		// keeping track of the changes, if the class asks for it
+		if err := stampBO(boSpecs, bObj, nil); err != nil {
+			return ErrorC(err, "Could not create object since it could not be stamped")
+		}
 
-	// we have stuff to do after the insertion ? yeah ? really ? let's do it now !
-	if err := bObj.ChangeAfterInsert(bloCtx); err != nil {
-		return ErrorC(err, "Could not post-insert object since it got an error")
-	}
+		// check of the constraints declared in the specs
+		if err := ValidateBO(boSpecs, bObj); err != nil {
+			return ErrorC(err, "Could not create object since it does not comply with its specs")
+		}
 
-	// // 'guess everything is alrite here
-	// return nilreturn nil
-	return nil
+		// check of "functional / business" validity
+		if err := bObj.IsValid(bloCtx); err != nil {
+			return ErrorC(err, "Could not create object since it is not valid")
+		}
+
+		// setting some tracking info
+		// bObj.SetCreatedByID(biContext.GetCurrentUser().GetID())
+		// bObj.SetCreatedBy(biContext.GetCurrentUser().GetLabel())
+		// bObj.SetCreation(core.Now())
+		// bObj.Set__BOBJ__Status(__BOBJ__StatusCREATED)
+
+		// pushing to the DB ! We're going to add a new line within the __BOBJ__'s table
+		if err := dbInsert(daoCtx, boSpecs, bObj); err != nil {
+			return ErrorC(err, "Could not create object because of a problem with the DB")
+		}
+
+		// we have stuff to do after the insertion ? yeah ? really ? let's do it now !
+		if err := bObj.ChangeAfterInsert(bloCtx); err != nil {
+			return ErrorC(err, "Could not post-insert object since it got an error")
+		}
+
+		// 'guess everything is alrite here
+		return nil
+	})
 }
 
-func LoadBOs[ResourceType IBusinessObject](bloCtx BloContext, boSpecs IBusinessObjectSpecs, loadingType LoadingType) ([]ResourceType, error) {
-	// func LoadBOs(bloCtx BloContext, boSpecs IBusinessObjectSpecs, loadingType LoadingType) ([]ResourceType, error) {
-	loadedBOs, errLoad := dbLoadList[ResourceType](bloCtx.GetDaoContext(), boSpecs)
-	// loadedBOs, errLoad := dbLoadList(bloCtx.GetDaoContext(), boSpecs)
+// Loads all the BOs of the given class, with only the given fields - along with the ID - if any are given,
+// or else the fields defined for the loading type; if the BOs can be instances of the class' subclasses, e.g.
+// for an abstract class with an inheritance strategy, then ResourceType must be IBusinessObject
+func LoadBOs[ResourceType IBusinessObject](bloCtx BloContext, boSpecs IBusinessObjectSpecs, loadingType LoadingType,
+	fieldNames ...string) ([]ResourceType, error) {
+	projection := getProjection(boSpecs, loadingType, fieldNames)
+	loadedBOs, errLoad := dbLoadList[ResourceType](bloCtx.GetDaoContext(), boSpecs, projection...)
 
 	if errLoad != nil {
 		return nil, ErrorC(errLoad, "error while loading a list of '%s'", boSpecs.base().name)
 	}
 
+	// reading the relationships, using the loading type
+	if errRelated := loadRelatedBOs(bloCtx, loadedBOs, getProjectedScenarios(boSpecs, loadingType, projection)); errRelated != nil {
+		return nil, errRelated
+	}
+
 	// TODO add post read, i.e.:
-	// - reading the links, using the LoadingType
-	// - on each BO: setting the loadingID + check if reading is ok, then do after read changes
+	// - on each BO: setting the loadingID + check if reading is ok
+	for i, loadedBO := range loadedBOs {
+		if errAfter := loadedBO.ChangeAfterRead(bloCtx); errAfter != nil {
+			return nil, ErrorC(errAfter, "error while post-reading '%s' #%d", boSpecs.base().name, i)
+		}
+	}
 
 	return loadedBOs, nil
 }
 
-func ReadBO(bloCtx BloContext, idProp IField, idPropVal string, loadingType LoadingType) (IBusinessObject, error) {
-	loadedBOs, errLoad := dbLoadOne(bloCtx.GetDaoContext(), idProp, idPropVal)
+// Loads the children of the given parent BO, through the "child to parent" relationship, with only the given
+// fields - along with the ID - if any are given, or else the fields defined for the loading type
+func LoadChildBOs[ResourceType IBusinessObject](bloCtx BloContext, childToParent *Relationship, parentID string,
+	loadingType LoadingType, fieldNames ...string) ([]ResourceType, error) {
+	boSpecs := childToParent.ownerSpecs()
 
+	projection := getProjection(boSpecs, loadingType, fieldNames)
+	loadedBOs, errLoad := dbLoadMany[ResourceType](bloCtx.GetDaoContext(), childToParent, []string{parentID}, projection...)
 	if errLoad != nil {
-		return nil, ErrorC(errLoad, "error while loading one instance of '%s' (%s)", idProp.ownerSpecs().base().name, idPropVal)
+		return nil, ErrorC(errLoad, "error while loading the '%s' of '%s' %s", boSpecs.base().name, childToParent.getName(), parentID)
 	}
 
-	// TODO add post read
+	if errRelated := loadRelatedBOs(bloCtx, loadedBOs, getProjectedScenarios(boSpecs, loadingType, projection)); errRelated != nil {
+		return nil, errRelated
+	}
+
+	for i, loadedBO := range loadedBOs {
+		if errAfter := loadedBO.ChangeAfterRead(bloCtx); errAfter != nil {
+			return nil, ErrorC(errAfter, "error while post-reading '%s' #%d", boSpecs.base().name, i)
+		}
+	}
 
 	return loadedBOs, nil
 }
 
-func DeleteBO(bloCtx BloContext, idProp IField, idPropVal string) (IBusinessObject, error) {
-	loadedBOs, errLoad := dbRemoveOne(bloCtx.GetDaoContext(), idProp, idPropVal)
+// Loads the BOs whose JSON field has the given value at the given path, e.g. "$.address.city"
+func LoadBOsByJSONPath[ResourceType IBusinessObject](bloCtx BloContext, jsonField *JSONField, path string, value string) ([]ResourceType, error) {
+	boSpecs := jsonField.ownerSpecs()
 
+	loadedBOs, errLoad := dbLoadByJSONPath[ResourceType](bloCtx.GetDaoContext(), jsonField, path, value)
 	if errLoad != nil {
-		return nil, ErrorC(errLoad, "error while deleting one instance of '%s' (%s)", idProp.ownerSpecs().base().name, idPropVal)
+		return nil, ErrorC(errLoad, "error while loading the '%s' having '%s' = %s in '%s'", boSpecs.base().name, path, value, jsonField.getName())
 	}
 
-	// TODO add post read
+	for i, loadedBO := range loadedBOs {
+		if errAfter := loadedBO.ChangeAfterRead(bloCtx); errAfter != nil {
+			return nil, ErrorC(errAfter, "error while post-reading '%s' #%d", boSpecs.base().name, i)
+		}
+	}
 
 	return loadedBOs, nil
 }
 
+// Loads the BO for which the given property has the given value, with only the given fields - along with the ID -
+// if any are given, or else the fields defined for the loading type
+func ReadBO(bloCtx BloContext, idProp IField, idPropVal string, loadingType LoadingType, fieldNames ...string) (IBusinessObject, error) {
+	boSpecs := idProp.ownerSpecs()
+	projection := getProjection(boSpecs, loadingType, fieldNames)
+	loadedBO, errLoad := dbLoadOne(bloCtx.GetDaoContext(), idProp, idPropVal, projection...)
+
+	if errLoad != nil {
+		return nil, ErrorC(errLoad, "error while loading one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+	}
+
+	scenarios := getProjectedScenarios(boSpecs, loadingType, projection)
+	if errRelated := loadRelatedBOs(bloCtx, []IBusinessObject{loadedBO}, scenarios); errRelated != nil {
+		return nil, errRelated
+	}
+
+	if errAfter := loadedBO.ChangeAfterRead(bloCtx); errAfter != nil {
+		return nil, ErrorC(errAfter, "error while post-reading one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+	}
+
+	return loadedBO, nil
+}
+
+func DeleteBO(bloCtx BloContext, idProp IField, idPropVal string) (deletedBO IBusinessObject, err error) {
+	boSpecs := idProp.ownerSpecs()
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	err = daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// we need the BO as it is persisted, to know its ID, and pass it to the hooks
+		loadedBO, errLoad := dbLoadOne(daoCtx, idProp, idPropVal)
+		if errLoad != nil {
+			return ErrorC(errLoad, "error while loading one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+		}
+
+		if errBefore := loadedBO.ChangeBeforeDelete(bloCtx); errBefore != nil {
+			return ErrorC(errBefore, "could not delete one instance of '%s' (%s) since the pre-delete got an error",
+				boSpecs.base().name, idPropVal)
+		}
+
+		if errRemove := dbRemoveOne(daoCtx, boSpecs, loadedBO.GetID()); errRemove != nil {
+			return ErrorC(errRemove, "error while deleting one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+		}
+
+		if errAfter := loadedBO.ChangeAfterDelete(bloCtx); errAfter != nil {
+			return ErrorC(errAfter, "could not post-delete one instance of '%s' (%s) since it got an error",
+				boSpecs.base().name, idPropVal)
+		}
+
+		deletedBO = loadedBO
+
+		return nil
+	})
+
+	// the attached files are only removed once we're sure the BO is gone
+	if err == nil {
+		deleteAttachedFiles(boSpecs, deletedBO)
+	}
+
+	return
+}
+
 func UpdateBO(bloCtx BloContext, input IBusinessObject, loadingType LoadingType) error {
-	if errUpd := dbUpdate(bloCtx.GetDaoContext(), input); errUpd != nil {
-		return ErrorC(errUpd, "error while updating one instance of '%T' (ID = %d)", input, input.GetID())
+	// the business object must have been persisted already
+	if input.GetID() == "" {
+		return Error("Could not update object '%T' since it has no ID", input)
+	}
+
+	boSpecs := specsOf(input)
+	if boSpecs == nil {
+		return Error("Could not update object '%T' since no specs are registered for its class", input)
+	}
+
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	return daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// the BO is locked, so that its version is incremented from the very state being overwritten
+		if errLock := dbLockOne(daoCtx, boSpecs.ID(), string(input.GetID())); errLock != nil {
+			return ErrorC(errLock, "error while locking '%T' (ID = %s)", input, input.GetID())
+		}
+
+		// the hooks may need to compare the input with the BO as it is currently persisted
+		previous, errLoad := dbLoadOne(daoCtx, boSpecs.ID(), string(input.GetID()))
+		if errLoad != nil {
+			return ErrorC(errLoad, "error while loading the current state of '%T' (ID = %s)", input, input.GetID())
+		}
+
+		if errBefore := input.ChangeBeforeUpdate(bloCtx, previous); errBefore != nil {
+			return ErrorC(errBefore, "could not update '%T' (ID = %s) since the pre-update got an error", input, input.GetID())
+		}
+
+		// keeping track of the changes, if the class asks for it
+		if errStamp := stampBO(boSpecs, input, previous); errStamp != nil {
+			return ErrorC(errStamp, "could not update '%T' (ID = %s) since it could not be stamped", input, input.GetID())
+		}
+
+		// check of the constraints declared in the specs
+		if errSpecs := ValidateBO(boSpecs, input); errSpecs != nil {
+			return ErrorC(errSpecs, "could not update '%T' (ID = %s) since it does not comply with its specs", input, input.GetID())
+		}
+
+		// check of "functional / business" validity
+		if errValid := input.IsValid(bloCtx); errValid != nil {
+			return ErrorC(errValid, "could not update '%T' (ID = %s) since it is not valid", input, input.GetID())
+		}
+
+		if errUpd := dbUpdate(daoCtx, boSpecs, input); errUpd != nil {
+			return ErrorC(errUpd, "error while updating one instance of '%T' (ID = %s)", input, input.GetID())
+		}
+
+		if errAfter := input.ChangeAfterUpdate(bloCtx, previous); errAfter != nil {
+			return ErrorC(errAfter, "could not post-update '%T' (ID = %s) since it got an error", input, input.GetID())
+		}
+
+		return nil
+	})
+}
+
+// ------------------------------------------------------------------------------------------------
+// Bulk operations, i.e. on many BOs of the same class at once
+// ------------------------------------------------------------------------------------------------
+
+// runs the pre-insert changes, the specs & validity checks on the given BO
+func beforeInsertingBO(bloCtx BloContext, boSpecs IBusinessObjectSpecs, num int, bObj IBusinessObject) error {
+	if err := bObj.ChangeBeforeInsert(bloCtx); err != nil {
+		return ErrorC(err, "Could not create object #%d since the pre-insert got an error", num)
+	}
+
+	if err := stampBO(boSpecs, bObj, nil); err != nil {
+		return ErrorC(err, "Could not create object #%d since it could not be stamped", num)
+	}
+
+	if err := ValidateBO(boSpecs, bObj); err != nil {
+		return ErrorC(err, "Could not create object #%d since it does not comply with its specs", num)
+	}
+
+	if err := bObj.IsValid(bloCtx); err != nil {
+		return ErrorC(err, "Could not create object #%d since it is not valid", num)
+	}
+
+	return nil
+}
+
+// runs the pre-update changes, the specs & validity checks on the given BO
+func beforeUpdatingBO(bloCtx BloContext, boSpecs IBusinessObjectSpecs, num int, bObj, previous IBusinessObject) error {
+	if err := bObj.ChangeBeforeUpdate(bloCtx, previous); err != nil {
+		return ErrorC(err, "Could not update object #%d since the pre-update got an error", num)
+	}
+
+	if err := stampBO(boSpecs, bObj, previous); err != nil {
+		return ErrorC(err, "Could not update object #%d since it could not be stamped", num)
+	}
+
+	if err := ValidateBO(boSpecs, bObj); err != nil {
+		return ErrorC(err, "Could not update object #%d since it does not comply with its specs", num)
+	}
+
+	if err := bObj.IsValid(bloCtx); err != nil {
+		return ErrorC(err, "Could not update object #%d since it is not valid", num)
+	}
+
+	return nil
+}
+
+// increments the version of the given BO, and sets its last modification date, if its class keeps track of them;
+// the previous state is nil for a BO being created
+func stampBO(boSpecs IBusinessObjectSpecs, bObj, previous IBusinessObject) error {
+	class := getClass(boSpecs)
+
+	if versionField := boSpecs.base().versionField; versionField != nil {
+		version := int64(0)
+		if previous != nil {
+			if previousVersion := class.GetValueAsString(previous, versionField.getName()); previousVersion != "" {
+				var errConv error
+				if version, errConv = strconv.ParseInt(previousVersion, 10, 64); errConv != nil {
+					return ErrorC(errConv, "Invalid version '%s' for '%s' %s", previousVersion, boSpecs.base().name, previous.GetID())
+				}
+			}
+		}
+
+		if errSet := class.SetValueAsString(bObj, versionField.getName(), strconv.FormatInt(version+1, 10)); errSet != nil {
+			return ErrorC(errSet, "Could not set the version of a '%s' object", boSpecs.base().name)
+		}
+	}
+
+	if lastModifiedField := boSpecs.base().lastModifiedField; lastModifiedField != nil {
+		if errSet := class.SetValueAsString(bObj, lastModifiedField.getName(), core.DateToString(core.Now())); errSet != nil {
+			return ErrorC(errSet, "Could not set the last modification date of a '%s' object", boSpecs.base().name)
+		}
 	}
 
 	return nil
 }
+
+// Controls, DB-inserts with as few queries as possible, post-treats the given BOs, which must be of the given class
+func CreateBOs[ResourceType IBusinessObject](bloCtx BloContext, boSpecs IBusinessObjectSpecs, bObjs []ResourceType) error {
+	if len(bObjs) == 0 {
+		return nil
+	}
+
+	// the business objects should not have an ID already
+	for i, bObj := range bObjs {
+		if bObj.GetID() != "" {
+			return Error("Could not create object #%d since it already has an ID (%s)", i, bObj.GetID())
+		}
+	}
+
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	return daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// pre-insert changes & validity checks, BO by BO
+		for i, bObj := range bObjs {
+			if err := beforeInsertingBO(bloCtx, boSpecs, i, bObj); err != nil {
+				return err
+			}
+		}
+
+		// pushing to the DB, all at once
+		if err := dbInsertMany(daoCtx, boSpecs, bObjs); err != nil {
+			return ErrorC(err, "Could not create %d '%s' objects because of a problem with the DB", len(bObjs), boSpecs.base().name)
+		}
+
+		// post-insert changes, BO by BO
+		for i, bObj := range bObjs {
+			if err := bObj.ChangeAfterInsert(bloCtx); err != nil {
+				return ErrorC(err, "Could not post-insert object #%d since it got an error", i)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Controls, DB-updates with as few queries as possible, post-treats the given BOs, which must be of the given class
+func UpdateBOs[ResourceType IBusinessObject](bloCtx BloContext, boSpecs IBusinessObjectSpecs, bObjs []ResourceType) error {
+	if len(bObjs) == 0 {
+		return nil
+	}
+
+	// the business objects must have been persisted already
+	for i, bObj := range bObjs {
+		if bObj.GetID() == "" {
+			return Error("Could not update object #%d since it has no ID", i)
+		}
+	}
+
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	return daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// the hooks may need to compare the inputs with the BOs as they are currently persisted
+		previousBOs, errLoad := dbLoadMany[IBusinessObject](daoCtx, boSpecs.ID(), getIDsAsStrings(bObjs))
+		if errLoad != nil {
+			return ErrorC(errLoad, "Could not load the current state of %d '%s' objects", len(bObjs), boSpecs.base().name)
+		}
+
+		previousByID := map[BObjID]IBusinessObject{}
+		for _, previousBO := range previousBOs {
+			previousByID[previousBO.GetID()] = previousBO
+		}
+
+		// pre-update changes & validity checks, BO by BO
+		for i, bObj := range bObjs {
+			if previousByID[bObj.GetID()] == nil {
+				return Error("Could not update object #%d since there's no '%s' with ID %s", i, boSpecs.base().name, bObj.GetID())
+			}
+
+			if err := beforeUpdatingBO(bloCtx, boSpecs, i, bObj, previousByID[bObj.GetID()]); err != nil {
+				return err
+			}
+		}
+
+		// pushing to the DB, all at once
+		if err := dbUpdateMany(daoCtx, boSpecs, bObjs); err != nil {
+			return ErrorC(err, "Could not update %d '%s' objects because of a problem with the DB", len(bObjs), boSpecs.base().name)
+		}
+
+		// post-update changes, BO by BO
+		for i, bObj := range bObjs {
+			if err := bObj.ChangeAfterUpdate(bloCtx, previousByID[bObj.GetID()]); err != nil {
+				return ErrorC(err, "Could not post-update object #%d since it got an error", i)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Pre-treats, DB-deletes with as few queries as possible, post-treats the BOs having the IDs of the given ones,
+// which must be of the given class; the BOs are returned as they were persisted, in the same order, each ID only once
+func DeleteBOs[ResourceType IBusinessObject](bloCtx BloContext, boSpecs IBusinessObjectSpecs, bObjs []ResourceType) (deletedBOs []ResourceType, err error) {
+	if len(bObjs) == 0 {
+		return nil, nil
+	}
+
+	// the business objects must have been persisted already; and each of them is deleted - and goes through the
+	// hooks - only once, even if given several times
+	uniqueBOs := []ResourceType{}
+	givenIDs := map[BObjID]bool{}
+	for i, bObj := range bObjs {
+		if bObj.GetID() == "" {
+			return nil, Error("Could not delete object #%d since it has no ID", i)
+		}
+
+		if !givenIDs[bObj.GetID()] {
+			givenIDs[bObj.GetID()] = true
+			uniqueBOs = append(uniqueBOs, bObj)
+		}
+	}
+	bObjs = uniqueBOs
+
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	err = daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// we need the BOs as they are persisted, to pass them to the hooks
+		loadedBOs, errLoad := dbLoadMany[ResourceType](daoCtx, boSpecs.ID(), getIDsAsStrings(bObjs))
+		if errLoad != nil {
+			return ErrorC(errLoad, "Could not load the current state of %d '%s' objects", len(bObjs), boSpecs.base().name)
+		}
+
+		loadedByID := map[BObjID]ResourceType{}
+		for _, loadedBO := range loadedBOs {
+			loadedByID[loadedBO.GetID()] = loadedBO
+		}
+
+		// pre-delete changes, BO by BO
+		deletedBOs = make([]ResourceType, len(bObjs))
+		for i, bObj := range bObjs {
+			loadedBO, found := loadedByID[bObj.GetID()]
+			if !found {
+				return ErrorC(&NotFoundError{ClassName: string(boSpecs.base().name), Criteria: "ID " + string(bObj.GetID())},
+					"Could not delete object #%d", i)
+			}
+
+			if err := loadedBO.ChangeBeforeDelete(bloCtx); err != nil {
+				return ErrorC(err, "Could not delete object #%d since the pre-delete got an error", i)
+			}
+
+			deletedBOs[i] = loadedBO
+		}
+
+		// removing from the DB, all at once
+		if err := dbRemoveMany(daoCtx, boSpecs, getIDsAsStrings(loadedBOs)); err != nil {
+			return ErrorC(err, "Could not delete %d '%s' objects because of a problem with the DB", len(bObjs), boSpecs.base().name)
+		}
+
+		// post-delete changes, BO by BO
+		for i, deletedBO := range deletedBOs {
+			if err := deletedBO.ChangeAfterDelete(bloCtx); err != nil {
+				return ErrorC(err, "Could not post-delete object #%d since it got an error", i)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the attached files are only removed once we're sure the BOs are gone
+	for _, deletedBO := range deletedBOs {
+		deleteAttachedFiles(boSpecs, deletedBO)
+	}
+
+	return deletedBOs, nil
+}
+
+// Inserts the given BOs, or updates them if there already are BOs with the same value for the given key field,
+// which must be declared as unique; the insert or update hooks are run accordingly on each BO
+func UpsertBOs[ResourceType IBusinessObject](bloCtx BloContext, keyField IField, bObjs []ResourceType) error {
+	if len(bObjs) == 0 {
+		return nil
+	}
+
+	// the key field must be unique, or we could update several rows with 1 BO
+	boSpecs := keyField.ownerSpecs()
+	if !keyField.isUnique() {
+		return Error("Cannot upsert '%s' objects using field '%s' since it is not declared unique",
+			boSpecs.base().name, keyField.getName())
+	}
+
+	class := getClass(boSpecs)
+	keys := core.MapFn(bObjs, func(bObj ResourceType) string { return class.GetValueAsString(bObj, keyField.getName()) })
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	return daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// finding out which BOs already exist, to run the right hooks
+		previousBOs, errLoad := dbLoadMany[IBusinessObject](daoCtx, keyField, keys)
+		if errLoad != nil {
+			return ErrorC(errLoad, "Could not load the current state of %d '%s' objects", len(bObjs), boSpecs.base().name)
+		}
+
+		previousByKey := map[string]IBusinessObject{}
+		for _, previousBO := range previousBOs {
+			previousByKey[class.GetValueAsString(previousBO, keyField.getName())] = previousBO
+		}
+
+		// pre-insert or pre-update changes & validity checks, BO by BO
+		for i, bObj := range bObjs {
+			if previous := previousByKey[keys[i]]; previous != nil {
+				if err := beforeUpdatingBO(bloCtx, boSpecs, i, bObj, previous); err != nil {
+					return err
+				}
+			} else if err := beforeInsertingBO(bloCtx, boSpecs, i, bObj); err != nil {
+				return err
+			}
+		}
+
+		// pushing to the DB, all at once
+		inserted, errUpsert := dbUpsertMany(daoCtx, keyField, bObjs)
+		if errUpsert != nil {
+			return ErrorC(errUpsert, "Could not upsert %d '%s' objects because of a problem with the DB", len(bObjs), boSpecs.base().name)
+		}
+
+		// post-insert or post-update changes, BO by BO
+		for i, bObj := range bObjs {
+			if inserted[i] {
+				if err := bObj.ChangeAfterInsert(bloCtx); err != nil {
+					return ErrorC(err, "Could not post-insert object #%d since it got an error", i)
+				}
+			} else if err := bObj.ChangeAfterUpdate(bloCtx, previousByKey[keys[i]]); err != nil {
+				return ErrorC(err, "Could not post-update object #%d since it got an error", i)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,145 @@
+// ------------------------------------------------------------------------------------------------
+// Here we implement the generic business logic involved in attaching files to business objects
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// AttachFile stores the content of the given file, and sets its metadata into the given attachment field
+// of the BO having the given ID, which is then updated; the file previously attached, if any, is replaced
+func AttachFile(bloCtx BloContext, attachmentField *AttachmentField, id string, file *AttachedFile) (IBusinessObject, error) {
+	boSpecs := attachmentField.ownerSpecs()
+
+	storage := attachmentField.getStorage()
+	if storage == nil {
+		return nil, Error("No file storage available for '%s.%s'", boSpecs.base().name, attachmentField.getName())
+	}
+
+	// checking the file as declared, before overwriting anything
+	if fieldErrors := attachmentField.checkValue(attachmentToString(file.Attachment)); len(fieldErrors) > 0 {
+		return nil, &ValidationError{ClassName: string(boSpecs.base().name), FieldErrors: fieldErrors}
+	}
+
+	bObj, errRead := ReadBO(bloCtx, boSpecs.ID(), id, "")
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	// storing the content under a temporary key, while computing its actual size & checksum, so that the file
+	// currently attached is only replaced once the BO has been updated with the new metadata
+	storageKey := attachmentField.getStorageKey(bObj.GetID())
+	tmpStorageKey := storageKey + "." + uuid.NewString() + ".tmp"
+	hash := sha256.New()
+	size, errSave := storage.Save(tmpStorageKey, io.TeeReader(file.Content, hash))
+	if errSave != nil {
+		return nil, ErrorC(errSave, "Could not store the file attached to '%s' (%s)", boSpecs.base().name, id)
+	}
+
+	attachment := &Attachment{
+		Name:        file.Name,
+		Size:        size,
+		ContentType: file.ContentType,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}
+
+	class := getClass(boSpecs)
+	errUpdate := class.SetValueAsString(bObj, attachmentField.getName(), attachmentToString(attachment))
+	if errUpdate == nil {
+		errUpdate = UpdateBO(bloCtx, bObj, "")
+	}
+
+	if errUpdate != nil {
+		if errDelete := storage.Delete(tmpStorageKey); errDelete != nil {
+			slog.Error(fmt.Sprintf("Could not delete the file uploaded for '%s' (%s): %s", boSpecs.base().name, id, errDelete))
+		}
+
+		return nil, ErrorC(errUpdate, "Could not update '%s' (%s) with its new attachment", boSpecs.base().name, id)
+	}
+
+	// the BO now describes the new file, which can replace the previous one
+	if errMove := moveStoredFile(storage, tmpStorageKey, storageKey); errMove != nil {
+		return nil, ErrorC(errMove, "Could not replace the file attached to '%s' (%s)", boSpecs.base().name, id)
+	}
+
+	return bObj, nil
+}
+
+// OpenAttachedFile gives access to the file attached through the given field to the BO having the given ID;
+// the returned file's content must be closed by the caller
+func OpenAttachedFile(bloCtx BloContext, attachmentField *AttachmentField, id string) (*AttachedFile, error) {
+	boSpecs := attachmentField.ownerSpecs()
+
+	storage := attachmentField.getStorage()
+	if storage == nil {
+		return nil, Error("No file storage available for '%s.%s'", boSpecs.base().name, attachmentField.getName())
+	}
+
+	bObj, errRead := ReadBO(bloCtx, boSpecs.ID(), id, "")
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	attachment, errGet := getAttachment(bObj, attachmentField)
+	if errGet != nil {
+		return nil, errGet
+	}
+
+	if attachment == nil {
+		return nil, Error("No file attached to '%s' (%s) through '%s'", boSpecs.base().name, id, attachmentField.getName())
+	}
+
+	content, errOpen := storage.Open(attachmentField.getStorageKey(bObj.GetID()))
+	if errOpen != nil {
+		return nil, ErrorC(errOpen, "Could not open the file attached to '%s' (%s)", boSpecs.base().name, id)
+	}
+
+	return &AttachedFile{Attachment: attachment, Content: content}, nil
+}
+
+// removes the content of all the files attached to the given - deleted - BO; errors are only logged,
+// since the BO is gone anyway
+func deleteAttachedFiles(boSpecs IBusinessObjectSpecs, bObj IBusinessObject) {
+	for _, field := range boSpecs.base().fields {
+		if attachmentField, ok := field.(*AttachmentField); ok {
+			if attachment, _ := getAttachment(bObj, attachmentField); attachment != nil && attachmentField.getStorage() != nil {
+				if errDelete := attachmentField.getStorage().Delete(attachmentField.getStorageKey(bObj.GetID())); errDelete != nil {
+					slog.Error(fmt.Sprintf("Could not delete the file attached to '%s' (%s): %s", boSpecs.base().name, bObj.GetID(), errDelete))
+				}
+			}
+		}
+	}
+}
+
+// returns the metadata of the file attached to the given BO through the given field, if any
+func getAttachment(bObj IBusinessObject, attachmentField *AttachmentField) (*Attachment, error) {
+	valueAsString := getClass(attachmentField.ownerSpecs()).GetValueAsString(bObj, attachmentField.getName())
+	if attachmentField.isZeroValue(valueAsString) {
+		return nil, nil
+	}
+
+	attachment := &Attachment{}
+	if errUnmarshal := json.Unmarshal([]byte(valueAsString), attachment); errUnmarshal != nil {
+		return nil, ErrorC(errUnmarshal, "Could not read the metadata of the attached file '%s'", attachmentField.getName())
+	}
+
+	return attachment, nil
+}
+
+// the attachment's metadata, as they would be returned by a value mapper
+func attachmentToString(attachment *Attachment) string {
+	valueAsBytes, errMarshal := json.Marshal(attachment)
+	if errMarshal != nil {
+		panic(ErrorC(errMarshal, "Could not get the attachment '%s' as JSON", attachment.Name))
+	}
+
+	return string(valueAsBytes)
+}
@@ -0,0 +1,96 @@
+// ------------------------------------------------------------------------------------------------
+// Here we load, along with some business objects, the ones pointed by the relationships of a
+// loading type - and recursively, the ones pointed by the relationships of its sub-scenarios
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import core "github.com/aldesgroup/corego"
+
+// loads the BOs pointed by the relationships of the given scenarios, and sets them onto the given BOs
+func loadRelatedBOs[ResourceType IBusinessObject](bloCtx BloContext, bObjs []ResourceType, scenarios []*LoadingScenario) error {
+	if len(bObjs) == 0 {
+		return nil
+	}
+
+	for _, scenario := range scenarios {
+		relationship := scenario.relationship
+
+		relatedByOwnerID, related, errLoad := loadRelationship(bloCtx.GetDaoContext(), bObjs, relationship)
+		if errLoad != nil {
+			return ErrorC(errLoad, "error while loading the '%s' of %d '%s'", relationship.getName(), len(bObjs),
+				relationship.ownerSpecs().base().name)
+		}
+
+		for i, relatedBO := range related {
+			if errAfter := relatedBO.ChangeAfterRead(bloCtx); errAfter != nil {
+				return ErrorC(errAfter, "error while post-reading '%s' #%d", relatedBO.getClassName(), i)
+			}
+		}
+
+		for _, bObj := range bObjs {
+			class := classRegistry.items[bObj.getClassName()]
+			if errSet := class.SetRelated(bObj, relationship.getName(), relatedByOwnerID[bObj.GetID()]); errSet != nil {
+				return ErrorC(errSet, "could not set the '%s' of '%s' %s", relationship.getName(), bObj.getClassName(), bObj.GetID())
+			}
+		}
+
+		if errWith := loadRelatedBOs(bloCtx, related, scenario.with); errWith != nil {
+			return errWith
+		}
+	}
+
+	return nil
+}
+
+// loads the BOs pointed by the given relationship from the given BOs, also returned by ID of the BO pointing to them
+func loadRelationship[ResourceType IBusinessObject](daoCtx DaoContext, bObjs []ResourceType, relationship *Relationship) (
+	relatedByOwnerID map[BObjID][]IBusinessObject, related []IBusinessObject, err error) {
+	relatedByOwnerID = map[BObjID][]IBusinessObject{}
+
+	// the relationship's column is on the owners' table, and holds the IDs of the related BOs...
+	if relationship.needsColumn() {
+		ownerIDsByRelatedID := map[string][]BObjID{}
+		relatedIDs := []string{}
+		for _, bObj := range bObjs {
+			relatedID := classRegistry.items[bObj.getClassName()].GetValueAsString(bObj, relationship.getName())
+			if relatedID == "" {
+				continue
+			}
+			if ownerIDsByRelatedID[relatedID] == nil {
+				relatedIDs = append(relatedIDs, relatedID)
+			}
+			ownerIDsByRelatedID[relatedID] = append(ownerIDsByRelatedID[relatedID], bObj.GetID())
+		}
+
+		if len(relatedIDs) == 0 {
+			return relatedByOwnerID, nil, nil
+		}
+
+		if related, err = dbLoadMany[IBusinessObject](daoCtx, relationship.targets[0].ID(), relatedIDs); err != nil {
+			return nil, nil, err
+		}
+
+		for _, relatedBO := range related {
+			for _, ownerID := range ownerIDsByRelatedID[string(relatedBO.GetID())] {
+				relatedByOwnerID[ownerID] = append(relatedByOwnerID[ownerID], relatedBO)
+			}
+		}
+
+		return relatedByOwnerID, related, nil
+	}
+
+	// ... or else, the back ref's column is on the related BOs' table, and holds the IDs of the owners
+	backRef := relationship.backRefs[0]
+	ownerIDs := core.MapFn(bObjs, func(bObj ResourceType) string { return string(bObj.GetID()) })
+
+	if related, err = dbLoadMany[IBusinessObject](daoCtx, backRef, ownerIDs); err != nil {
+		return nil, nil, err
+	}
+
+	for _, relatedBO := range related {
+		ownerID := BObjID(classRegistry.items[relatedBO.getClassName()].GetValueAsString(relatedBO, backRef.getName()))
+		relatedByOwnerID[ownerID] = append(relatedByOwnerID[ownerID], relatedBO)
+	}
+
+	return relatedByOwnerID, related, nil
+}
@@ -0,0 +1,203 @@
+// ------------------------------------------------------------------------------------------------
+// Here we implement the generic patching of business objects, i.e. changing only some of their
+// fields - the ones declared patchable in their specs - from a partial JSON document
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+// Patch is a partial JSON document, giving the new values of some of a BO's fields, by field name
+type Patch map[string]json.RawMessage
+
+// Loads the BO for which the given property has the given value, applies the given patch onto it, controls it,
+// and DB-updates the columns that have changed; only the fields declared patchable can be found in the patch
+func PatchBO(bloCtx BloContext, idProp IField, idPropVal string, patch Patch) (patchedBO IBusinessObject, err error) {
+	boSpecs := idProp.ownerSpecs()
+	class := getClass(boSpecs)
+
+	// the patch must only contain patchable fields, with readable values
+	valuesAsStrings := map[string]string{}
+	fieldErrors := []*FieldError{}
+	for _, fieldName := range core.GetSortedKeys(patch) {
+		field := boSpecs.base().fields[fieldName]
+		if field == nil || !field.isPatchable() {
+			fieldErrors = append(fieldErrors, &FieldError{Field: fieldName, Rule: ValidationRulePATCHABLE})
+			continue
+		}
+
+		valueAsString, errValue := patchValueToString(field, patch[fieldName])
+		if errValue != nil {
+			fieldErrors = append(fieldErrors, newFieldError(field, ValidationRuleFORMAT, ""))
+			continue
+		}
+
+		valuesAsStrings[fieldName] = valueAsString
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, ErrorC(&ValidationError{ClassName: string(boSpecs.base().name), FieldErrors: fieldErrors},
+			"could not patch one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+	}
+
+	bloCtx, daoCtx := withSameDaoContext(bloCtx)
+
+	err = daoCtx.inTransaction(boSpecs.getInDB(), func() error {
+		// the BO is locked, so that its version is incremented from the very state being patched
+		if errLock := dbLockOne(daoCtx, idProp, idPropVal); errLock != nil {
+			return ErrorC(errLock, "error while locking one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+		}
+
+		// the BO to patch, and its current state, for the hooks & to know what's changed
+		loadedBO, errLoad := dbLoadOne(daoCtx, idProp, idPropVal)
+		if errLoad != nil {
+			return ErrorC(errLoad, "error while loading one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+		}
+
+		previous, errPrevious := copyBO(loadedBO)
+		if errPrevious != nil {
+			return ErrorC(errPrevious, "error while keeping the current state of '%s' (%s)", boSpecs.base().name, idPropVal)
+		}
+
+		// applying the patch
+		for _, fieldName := range core.GetSortedKeys(valuesAsStrings) {
+			if errSet := setPatchedValue(class, loadedBO, fieldName, valuesAsStrings[fieldName]); errSet != nil {
+				return ErrorC(&ValidationError{
+					ClassName:   string(boSpecs.base().name),
+					FieldErrors: []*FieldError{newFieldError(boSpecs.base().fields[fieldName], ValidationRuleFORMAT, "")},
+				}, "could not patch one instance of '%s' (%s): %s", boSpecs.base().name, idPropVal, errSet)
+			}
+		}
+
+		if errBefore := loadedBO.ChangeBeforeUpdate(bloCtx, previous); errBefore != nil {
+			return ErrorC(errBefore, "could not patch one instance of '%s' (%s) since the pre-update got an error",
+				boSpecs.base().name, idPropVal)
+		}
+
+		// keeping track of the changes, if the class asks for it
+		if errStamp := stampBO(boSpecs, loadedBO, previous); errStamp != nil {
+			return ErrorC(errStamp, "could not patch one instance of '%s' (%s) since it could not be stamped",
+				boSpecs.base().name, idPropVal)
+		}
+
+		// check of the constraints declared in the specs
+		if errSpecs := ValidateBO(boSpecs, loadedBO); errSpecs != nil {
+			return ErrorC(errSpecs, "could not patch one instance of '%s' (%s) since it does not comply with its specs",
+				boSpecs.base().name, idPropVal)
+		}
+
+		// check of "functional / business" validity
+		if errValid := loadedBO.IsValid(bloCtx); errValid != nil {
+			return ErrorC(errValid, "could not patch one instance of '%s' (%s) since it is not valid", boSpecs.base().name, idPropVal)
+		}
+
+		if errUpd := dbUpdateChanges(daoCtx, boSpecs, loadedBO, previous); errUpd != nil {
+			return ErrorC(errUpd, "error while patching one instance of '%s' (%s)", boSpecs.base().name, idPropVal)
+		}
+
+		if errAfter := loadedBO.ChangeAfterUpdate(bloCtx, previous); errAfter != nil {
+			return ErrorC(errAfter, "could not post-update one instance of '%s' (%s) since it got an error",
+				boSpecs.base().name, idPropVal)
+		}
+
+		patchedBO = loadedBO
+
+		return nil
+	})
+
+	return
+}
+
+// returns a copy of the given BO - its fields & the relationships it has a column for - made with the value mappers
+func copyBO(bObj IBusinessObject) (IBusinessObject, error) {
+	boSpecs := specsForName(bObj.getClassName())
+	if boSpecs == nil {
+		return nil, Error("No specs registered for class '%s'", bObj.getClassName())
+	}
+
+	class := getClass(boSpecs)
+	copied := class.NewObject().(IBusinessObject)
+	copied.setClassName(bObj.getClassName())
+
+	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
+		if errSet := class.SetValueAsString(copied, field.getName(), class.GetValueAsString(bObj, field.getName())); errSet != nil {
+			return nil, ErrorC(errSet, "Could not copy field '%s.%s'", boSpecs.base().name, field.getName())
+		}
+	}
+
+	for _, relationship := range boSpecs.base().relationshipsWithColumn {
+		if errSet := class.SetValueAsString(copied, relationship.getName(), class.GetValueAsString(bObj, relationship.getName())); errSet != nil {
+			return nil, ErrorC(errSet, "Could not copy relationship '%s.%s'", boSpecs.base().name, relationship.getName())
+		}
+	}
+
+	return copied, nil
+}
+
+// converts a value found in a patch into the string format expected by the value mappers
+func patchValueToString(field IField, rawValue json.RawMessage) (string, error) {
+	rawValue = bytes.TrimSpace(rawValue)
+	if len(rawValue) == 0 || string(rawValue) == "null" {
+		return "", nil
+	}
+
+	switch typeFamily := field.getTypeFamily(); {
+	case field.isMultiple() && typeFamily == utils.TypeFamilyENUM:
+		// the enum values are written like "1,3"
+		values := []int{}
+		if errUnmarshal := json.Unmarshal(rawValue, &values); errUnmarshal != nil {
+			return "", ErrorC(errUnmarshal, "Could not read the values of '%s' as an array of integers", field.getName())
+		}
+
+		return strings.Join(core.MapFn(values, strconv.Itoa), enumValuesSEPARATOR), nil
+
+	case field.isMultiple() || typeFamily == utils.TypeFamilyJSON || typeFamily == utils.TypeFamilyATTACHMENT:
+		// these values are handled as JSON documents by the value mappers
+		if !json.Valid(rawValue) {
+			return "", Error("Invalid JSON value for '%s'", field.getName())
+		}
+
+		return string(rawValue), nil
+
+	case rawValue[0] == '"':
+		value := ""
+		if errUnmarshal := json.Unmarshal(rawValue, &value); errUnmarshal != nil {
+			return "", ErrorC(errUnmarshal, "Could not read the value of '%s' as a string", field.getName())
+		}
+
+		return value, nil
+
+	case rawValue[0] == '[' || rawValue[0] == '{':
+		return "", Error("Field '%s' does not accept arrays or objects", field.getName())
+
+	default:
+		// numbers & booleans are passed as they are
+		if !json.Valid(rawValue) {
+			return "", Error("Invalid JSON value for '%s'", field.getName())
+		}
+
+		return string(rawValue), nil
+	}
+}
+
+// sets the given value with the value mappers, which may panic when the value cannot be read for the field's type
+func setPatchedValue(class IClass, bObj IBusinessObject, fieldName, valueAsString string) (err error) {
+	defer func() {
+		if panicked := recover(); panicked != nil {
+			err = Error("%v", panicked)
+		}
+	}()
+
+	if errSet := class.SetValueAsString(bObj, fieldName, valueAsString); errSet != nil {
+		return ErrorC(errSet, "Could not set '%s' to %s", fieldName, valueAsString)
+	}
+
+	return nil
+}
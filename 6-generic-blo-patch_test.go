@@ -0,0 +1,59 @@
+package goald
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+func TestPatchValueToString(t *testing.T) {
+	stringField := &StringField{field: newField(nil, "Name", false, utils.TypeFamilySTRING)}
+	intField := &IntField{numericField: numericField{field: newField(nil, "Age", false, utils.TypeFamilyINT)}}
+	boolField := &BoolField{field: newField(nil, "Active", false, utils.TypeFamilyBOOL)}
+	enumsField := &EnumField{field: newField(nil, "Roles", true, utils.TypeFamilyENUM)}
+	stringsField := &StringField{field: newField(nil, "Tags", true, utils.TypeFamilySTRING)}
+	jsonField := &JSONField{field: newField(nil, "Settings", false, utils.TypeFamilyJSON)}
+
+	tests := []struct {
+		name     string
+		field    IField
+		rawValue string
+		expected string
+		wantErr  bool
+	}{
+		{"null", stringField, `null`, "", false},
+		{"empty", stringField, ``, "", false},
+		{"blank", intField, `  `, "", false},
+		{"string", stringField, `"John"`, "John", false},
+		{"escaped string", stringField, `"a \"quoted\" name"`, `a "quoted" name`, false},
+		{"padded string", stringField, ` "John" `, "John", false},
+		{"integer", intField, `42`, "42", false},
+		{"negative integer", intField, `-7`, "-7", false},
+		{"quoted integer", intField, `"42"`, "42", false},
+		{"boolean", boolField, `true`, "true", false},
+		{"array for a single field", stringField, `["a"]`, "", true},
+		{"object for a single field", intField, `{"a":1}`, "", true},
+		{"invalid number", intField, `4x2`, "", true},
+		{"unterminated string", stringField, `"John`, "", true},
+		{"enum values", enumsField, `[1, 3]`, "1,3", false},
+		{"no enum values", enumsField, `[]`, "", false},
+		{"enum values as strings", enumsField, `["1"]`, "", true},
+		{"multiple strings", stringsField, `["a","b"]`, `["a","b"]`, false},
+		{"invalid multiple strings", stringsField, `["a",`, "", true},
+		{"JSON document", jsonField, `{"theme":"dark"}`, `{"theme":"dark"}`, false},
+		{"invalid JSON document", jsonField, `{"theme":`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patchValueToString(tt.field, json.RawMessage(tt.rawValue))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("patchValueToString(%s) error = %v, wantErr %v", tt.rawValue, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("patchValueToString(%s) = %q, expected %q", tt.rawValue, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// ------------------------------------------------------------------------------------------------
+// Here we implement the generic validation of business objects, against the constraints
+// declared in their specs, like mandatory fields, string sizes, numeric min / max, etc.
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
+	"github.com/shopspring/decimal"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Validation errors
+// ------------------------------------------------------------------------------------------------
+
+// ValidationRule is the name of a constraint declared in the specs
+type ValidationRule string
+
+const (
+	ValidationRuleMANDATORY    ValidationRule = "mandatory"   // the value must be non-zero
+	ValidationRuleMINxSIZE     ValidationRule = "minSize"     // the string value must have at least N characters
+	ValidationRuleMAXxSIZE     ValidationRule = "maxSize"     // the string value must have at most N characters, or the binary value N bytes
+	ValidationRuleMIN          ValidationRule = "min"         // the numeric value must be greater than or equal to a limit
+	ValidationRuleMAX          ValidationRule = "max"         // the numeric value must be lower than or equal to a limit
+	ValidationRuleONLY         ValidationRule = "only"        // the enum value - or each of them - must be one of the listed values
+	ValidationRulePATTERN      ValidationRule = "pattern"     // the string value must match a regular expression
+	ValidationRuleEMAIL        ValidationRule = "email"       // the string value must be an email address
+	ValidationRuleURL          ValidationRule = "url"         // the string value must be an absolute URL
+	ValidationRuleONExOF       ValidationRule = "oneOf"       // the string value must be one of the listed values
+	ValidationRulePRECISION    ValidationRule = "precision"   // the decimal value must fit the declared "precision,scale" format
+	ValidationRuleCUSTOM       ValidationRule = "custom"      // the string value must pass a custom validator, whose name is the limit
+	ValidationRuleCONTENTxTYPE ValidationRule = "contentType" // the attached file must have one of the listed content types
+	ValidationRuleMANDATORYxIF ValidationRule = "mandatoryIf" // the value must be non-zero, given another field's value
+	ValidationRuleCOMPARISON   ValidationRule = "comparison"  // the value must compare in a given way with another field's value
+	ValidationRuleATxLEAST     ValidationRule = "atLeast"     // the multiple relationship must point to at least N BOs
+	ValidationRulePATCHABLE    ValidationRule = "patchable"   // the field must exist and be declared patchable to be found in a patch
+	ValidationRuleFORMAT       ValidationRule = "format"      // the value must be readable for the field's type
+	ValidationRuleSELECTABLE   ValidationRule = "selectable"  // the property must exist to be asked for with the "fields" query param
+)
+
+// FieldError describes the violation of 1 rule by 1 field
+type FieldError struct {
+	Field string         `json:"Field"`           // the name of the faulty field
+	Rule  ValidationRule `json:"Rule"`            // the violated rule
+	Limit string         `json:"Limit,omitempty"` // the limit set by the rule, if any
+}
+
+func (thisErr *FieldError) String() string {
+	if thisErr.Limit == "" {
+		return thisErr.Field + " (" + string(thisErr.Rule) + ")"
+	}
+
+	return thisErr.Field + " (" + string(thisErr.Rule) + ": " + thisErr.Limit + ")"
+}
+
+func newFieldError(f IField, rule ValidationRule, limit string) *FieldError {
+	return &FieldError{Field: f.getName(), Rule: rule, Limit: limit}
+}
+
+// ValidationError gathers all the rules violated by a given business object
+type ValidationError struct {
+	ClassName   string        // the class of the invalid business object
+	FieldErrors []*FieldError // all the violations found
+}
+
+func (thisErr *ValidationError) Error() string {
+	return "Invalid '" + thisErr.ClassName + "' object: " +
+		strings.Join(core.MapFn(thisErr.FieldErrors, func(fieldErr *FieldError) string { return fieldErr.String() }), ", ")
+}
+
+// ------------------------------------------------------------------------------------------------
+// Validation of a whole business object
+// ------------------------------------------------------------------------------------------------
+
+// ValidateBO checks the given BO against the constraints & rules declared in the given specs, and returns
+// a *ValidationError listing all the violations, if any
+func ValidateBO(boSpecs IBusinessObjectSpecs, bObj IBusinessObject) error {
+	class := getClass(boSpecs)
+	fieldErrors := []*FieldError{}
+
+	for _, field := range core.GetSortedValues(boSpecs.base().fields) {
+		// not handling the ID field
+		if typeFamily := field.getTypeFamily(); typeFamily != utils.TypeFamilyUNKNOWN && typeFamily != utils.TypeFamilyRELATIONSHIPxMONOM {
+			if field.getName() != "ID" {
+				if field.isMultiple() && typeFamily != utils.TypeFamilyENUM {
+					fieldErrors = append(fieldErrors, checkMultipleValues(field, class.GetValueAsString(bObj, field.getName()))...)
+				} else {
+					fieldErrors = append(fieldErrors, field.checkValue(class.GetValueAsString(bObj, field.getName()))...)
+				}
+			}
+		}
+	}
+
+	for _, rule := range boSpecs.base().rules {
+		if fieldError := rule.check(class, bObj); fieldError != nil {
+			fieldErrors = append(fieldErrors, fieldError)
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return &ValidationError{ClassName: string(boSpecs.base().name), FieldErrors: fieldErrors}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------------
+// Validation of the fields, depending on their type
+// ------------------------------------------------------------------------------------------------
+
+// returns true if the given value is the zero value for this field's type
+func (f *field) isZeroValue(valueAsString string) bool {
+	// an empty list, whatever its items' type
+	if f.multiple {
+		return valueAsString == "" || valueAsString == "null" || valueAsString == "[]"
+	}
+
+	switch f.typeFamily {
+	case utils.TypeFamilyBOOL:
+		return valueAsString != "true"
+	case utils.TypeFamilyINT, utils.TypeFamilyBIGINT, utils.TypeFamilyDURATION, utils.TypeFamilyENUM:
+		return valueAsString == "" || valueAsString == "0"
+	case utils.TypeFamilyREAL, utils.TypeFamilyDOUBLE:
+		value, errParse := strconv.ParseFloat(valueAsString, 64)
+		return errParse != nil || value == 0
+	case utils.TypeFamilyDECIMAL:
+		value, errParse := decimal.NewFromString(valueAsString)
+		return errParse != nil || value.IsZero()
+	case utils.TypeFamilyJSON, utils.TypeFamilyATTACHMENT:
+		return valueAsString == "" || valueAsString == "null"
+	default:
+		return valueAsString == ""
+	}
+}
+
+// the constraints on a multiple - non-enum - field, given as a JSON array: the list must not be empty if mandatory,
+// and each of its items must pass the field's checks; only the errors of the 1st invalid item are returned
+func checkMultipleValues(field IField, valueAsString string) []*FieldError {
+	if field.isMandatory() && field.isZeroValue(valueAsString) {
+		return []*FieldError{newFieldError(field, ValidationRuleMANDATORY, "")}
+	}
+
+	items, errItems := stringToMultipleValues(field, valueAsString)
+	if errItems != nil {
+		return []*FieldError{newFieldError(field, ValidationRuleFORMAT, "")}
+	}
+
+	for _, item := range items {
+		if fieldErrors := field.checkValue(item); len(fieldErrors) > 0 {
+			return fieldErrors
+		}
+	}
+
+	return nil
+}
+
+// the constraints common to all the fields
+func (f *field) checkValue(valueAsString string) []*FieldError {
+	if f.mandatory && f.isZeroValue(valueAsString) {
+		return []*FieldError{newFieldError(f, ValidationRuleMANDATORY, "")}
+	}
+
+	return nil
+}
+
+func (sf *StringField) checkValue(valueAsString string) (fieldErrors []*FieldError) {
+	if fieldErrors = sf.field.checkValue(valueAsString); fieldErrors != nil || valueAsString == "" {
+		return
+	}
+
+	if length := utf8.RuneCountInString(valueAsString); sf.size > 0 && length > sf.size {
+		fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRuleMAXxSIZE, strconv.Itoa(sf.size)))
+	} else if sf.atLeast > 0 && length < sf.atLeast {
+		fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRuleMINxSIZE, strconv.Itoa(sf.atLeast)))
+	}
+
+	if sf.pattern != nil && !sf.pattern.MatchString(valueAsString) {
+		fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRulePATTERN, sf.pattern.String()))
+	}
+
+	if sf.email && !emailREGEX.MatchString(valueAsString) {
+		fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRuleEMAIL, ""))
+	}
+
+	if sf.url && !isAbsoluteURL(valueAsString) {
+		fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRuleURL, ""))
+	}
+
+	if len(sf.oneOf) > 0 && !slices.Contains(sf.oneOf, valueAsString) {
+		fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRuleONExOF, strings.Join(sf.oneOf, ", ")))
+	}
+
+	for _, validator := range sf.validators {
+		if !validator.fn(valueAsString) {
+			fieldErrors = append(fieldErrors, newFieldError(sf, ValidationRuleCUSTOM, validator.name))
+		}
+	}
+
+	return
+}
+
+// the same - simple - regular expression as the one used by the web & native apps
+var emailREGEX = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+
+// returns true if the given value is a URL with a scheme & a host
+func isAbsoluteURL(valueAsString string) bool {
+	parsedURL, errParse := url.ParseRequestURI(valueAsString)
+	return errParse == nil && parsedURL.Scheme != "" && parsedURL.Host != ""
+}
+
+func (f *IntField) checkValue(valueAsString string) []*FieldError {
+	return checkNumericValue(&f.numericField, valueAsString, int64(f.min), int64(f.max),
+		func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) },
+		func(limit int64) string { return strconv.FormatInt(limit, 10) })
+}
+
+func (f *BigIntField) checkValue(valueAsString string) []*FieldError {
+	return checkNumericValue(&f.numericField, valueAsString, f.min, f.max,
+		func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) },
+		func(limit int64) string { return strconv.FormatInt(limit, 10) })
+}
+
+func (f *RealField) checkValue(valueAsString string) []*FieldError {
+	return checkNumericValue(&f.numericField, valueAsString, float64(f.min), float64(f.max),
+		func(s string) (float64, error) { return strconv.ParseFloat(s, 32) },
+		func(limit float64) string { return strconv.FormatFloat(limit, 'f', -1, 32) })
+}
+
+func (f *DoubleField) checkValue(valueAsString string) []*FieldError {
+	return checkNumericValue(&f.numericField, valueAsString, f.min, f.max,
+		func(s string) (float64, error) { return strconv.ParseFloat(s, 64) },
+		func(limit float64) string { return strconv.FormatFloat(limit, 'f', -1, 64) })
+}
+
+// the min / max constraints, for all the numeric types
+func checkNumericValue[N int64 | float64](f *numericField, valueAsString string, min, max N,
+	parse func(string) (N, error), format func(N) string) (fieldErrors []*FieldError) {
+	if fieldErrors = f.field.checkValue(valueAsString); fieldErrors != nil || valueAsString == "" {
+		return
+	}
+
+	value, errParse := parse(valueAsString)
+	if errParse != nil {
+		return []*FieldError{newFieldError(f, ValidationRuleFORMAT, "")}
+	}
+
+	if f.minSet && value < min {
+		fieldErrors = append(fieldErrors, newFieldError(f, ValidationRuleMIN, format(min)))
+	} else if f.maxSet && value > max {
+		fieldErrors = append(fieldErrors, newFieldError(f, ValidationRuleMAX, format(max)))
+	}
+
+	return
+}
+
+func (f *DecimalField) checkValue(valueAsString string) (fieldErrors []*FieldError) {
+	if fieldErrors = f.field.checkValue(valueAsString); fieldErrors != nil || valueAsString == "" {
+		return
+	}
+
+	value, errParse := decimal.NewFromString(valueAsString)
+	if errParse != nil {
+		return []*FieldError{newFieldError(f, ValidationRuleFORMAT, "")}
+	}
+
+	// not too many digits after the decimal point, nor before
+	integerDigits := len(value.Abs().Truncate(0).String())
+	if value.Abs().LessThan(decimal.NewFromInt(1)) {
+		integerDigits = 0
+	}
+
+	if !value.Equal(value.Truncate(int32(f.scale))) || integerDigits > f.precision-f.scale {
+		fieldErrors = append(fieldErrors, newFieldError(f, ValidationRulePRECISION, fmt.Sprintf("%d,%d", f.precision, f.scale)))
+	}
+
+	return
+}
+
+func (f *BinaryField) checkValue(valueAsString string) (fieldErrors []*FieldError) {
+	if fieldErrors = f.field.checkValue(valueAsString); fieldErrors != nil || f.maxSize == 0 {
+		return
+	}
+
+	value, errDecode := base64.StdEncoding.DecodeString(valueAsString)
+	if errDecode != nil {
+		return []*FieldError{newFieldError(f, ValidationRuleFORMAT, "")}
+	}
+
+	if len(value) > f.maxSize {
+		fieldErrors = append(fieldErrors, newFieldError(f, ValidationRuleMAXxSIZE, strconv.Itoa(f.maxSize)))
+	}
+
+	return
+}
+
+func (f *AttachmentField) checkValue(valueAsString string) (fieldErrors []*FieldError) {
+	if fieldErrors = f.field.checkValue(valueAsString); fieldErrors != nil || f.isZeroValue(valueAsString) {
+		return
+	}
+
+	attachment := &Attachment{}
+	if errUnmarshal := json.Unmarshal([]byte(valueAsString), attachment); errUnmarshal != nil {
+		return []*FieldError{newFieldError(f, ValidationRuleFORMAT, "")}
+	}
+
+	if f.maxSize > 0 && attachment.Size > f.maxSize {
+		fieldErrors = append(fieldErrors, newFieldError(f, ValidationRuleMAXxSIZE, strconv.FormatInt(f.maxSize, 10)))
+	}
+
+	if len(f.contentTypes) > 0 && !slices.Contains(f.contentTypes, attachment.ContentType) {
+		fieldErrors = append(fieldErrors, newFieldError(f, ValidationRuleCONTENTxTYPE, strings.Join(f.contentTypes, ", ")))
+	}
+
+	return
+}
+
+func (f *EnumField) checkValue(valueAsString string) (fieldErrors []*FieldError) {
+	if fieldErrors = f.field.checkValue(valueAsString); fieldErrors != nil || len(f.onlyValues) == 0 || f.isZeroValue(valueAsString) {
+		return
+	}
+
+	// with a multiple field, each of the values must be allowed
+	values := []string{valueAsString}
+	if f.multiple {
+		values = strings.Split(valueAsString, enumValuesSEPARATOR)
+	}
+
+	for _, value := range values {
+		if !slices.ContainsFunc(f.onlyValues, func(onlyValue IEnum) bool { return strconv.Itoa(onlyValue.Val()) == value }) {
+			return []*FieldError{newFieldError(f, ValidationRuleONLY,
+				strings.Join(core.MapFn(f.onlyValues, func(onlyValue IEnum) string { return onlyValue.String() }), ", "))}
+		}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------------------------------------------
+// Validation of the rules involving several properties
+// ------------------------------------------------------------------------------------------------
+
+func (rule *mandatoryIfRule) check(class IClass, bObj IBusinessObject) *FieldError {
+	if class.GetValueAsString(bObj, rule.otherField.getName()) == rule.otherValue &&
+		rule.field.isZeroValue(class.GetValueAsString(bObj, rule.field.getName())) {
+		return newFieldError(rule.field, ValidationRuleMANDATORYxIF, rule.otherField.getName()+" == "+rule.otherValue)
+	}
+
+	return nil
+}
+
+func (rule *comparisonRule) check(class IClass, bObj IBusinessObject) *FieldError {
+	value := class.GetValueAsString(bObj, rule.field.getName())
+	otherValue := class.GetValueAsString(bObj, rule.otherField.getName())
+
+	// no value, no comparison - it's up to the mandatory constraints to tell if this is OK
+	if value == "" || otherValue == "" {
+		return nil
+	}
+
+	if !rule.comparator.accepts(compareValues(rule.field, value, otherValue)) {
+		return newFieldError(rule.field, ValidationRuleCOMPARISON, string(rule.comparator)+" "+rule.otherField.getName())
+	}
+
+	return nil
+}
+
+func (rule *atLeastRule) check(class IClass, bObj IBusinessObject) *FieldError {
+	if class.GetNumberOf(bObj, rule.relationship.getName()) < rule.nb {
+		return &FieldError{Field: rule.relationship.getName(), Rule: ValidationRuleATxLEAST, Limit: strconv.Itoa(rule.nb)}
+	}
+
+	return nil
+}
+
+// compares 2 values of the same type as the given field, and returns -1, 0 or 1
+func compareValues(f IField, value, otherValue string) int {
+	switch f.getTypeFamily() {
+	case utils.TypeFamilyINT, utils.TypeFamilyBIGINT, utils.TypeFamilyREAL, utils.TypeFamilyDOUBLE, utils.TypeFamilyDURATION, utils.TypeFamilyENUM:
+		number, _ := strconv.ParseFloat(value, 64)
+		otherNumber, _ := strconv.ParseFloat(otherValue, 64)
+		return cmp.Compare(number, otherNumber)
+	case utils.TypeFamilyDECIMAL:
+		return decimal.RequireFromString(value).Cmp(decimal.RequireFromString(otherValue))
+	case utils.TypeFamilyDATE:
+		return core.StringToDate(value, f.getName()).Compare(*core.StringToDate(otherValue, f.getName()))
+	default:
+		return strings.Compare(value, otherValue)
+	}
+}
@@ -0,0 +1,65 @@
+package goald
+
+import (
+	"testing"
+
+	"github.com/aldesgroup/goald/features/utils"
+)
+
+func TestDecimalFieldCheckValue(t *testing.T) {
+	newDecimalField := func(precision, scale int, mandatory bool) *DecimalField {
+		f := &DecimalField{field: newField(nil, "Amount", false, utils.TypeFamilyDECIMAL), precision: precision, scale: scale}
+		f.mandatory = mandatory
+
+		return f
+	}
+
+	amount := newDecimalField(6, 2, false)
+	mandatoryAmount := newDecimalField(6, 2, true)
+	ratio := newDecimalField(3, 3, false)
+	count := newDecimalField(4, 0, false)
+
+	tests := []struct {
+		name     string
+		field    *DecimalField
+		value    string
+		expected ValidationRule // "" if the value is valid
+	}{
+		{"empty", amount, "", ""},
+		{"zero", amount, "0", ""},
+		{"fitting", amount, "9876.06", ""},
+		{"less digits after the point", amount, "9876.1", ""},
+		{"trailing zeros after the point", amount, "12.3400", ""},
+		{"negative", amount, "-9876.06", ""},
+		{"below 1", amount, "0.99", ""},
+		{"too many digits after the point", amount, "1.234", ValidationRulePRECISION},
+		{"too many digits before the point", amount, "12345.6", ValidationRulePRECISION},
+		{"too many digits before the point, negative", amount, "-12345", ValidationRulePRECISION},
+		{"not a number", amount, "12,5", ValidationRuleFORMAT},
+		{"not a number at all", amount, "abc", ValidationRuleFORMAT},
+		{"mandatory, empty", mandatoryAmount, "", ValidationRuleMANDATORY},
+		{"mandatory, zero", mandatoryAmount, "0.00", ValidationRuleMANDATORY},
+		{"mandatory, set", mandatoryAmount, "0.01", ""},
+		{"only decimals, fitting", ratio, "0.125", ""},
+		{"only decimals, too many", ratio, "0.1255", ValidationRulePRECISION},
+		{"only decimals, integer part", ratio, "1.5", ValidationRulePRECISION},
+		{"no decimals, fitting", count, "9999", ""},
+		{"no decimals, decimal part", count, "1.5", ValidationRulePRECISION},
+		{"no decimals, too big", count, "10000", ValidationRulePRECISION},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldErrors := tt.field.checkValue(tt.value)
+
+			switch {
+			case tt.expected == "" && len(fieldErrors) > 0:
+				t.Errorf("checkValue(%s) = %s, expected no error", tt.value, fieldErrors[0])
+			case tt.expected != "" && len(fieldErrors) == 0:
+				t.Errorf("checkValue(%s) = no error, expected %s", tt.value, tt.expected)
+			case tt.expected != "" && (len(fieldErrors) != 1 || fieldErrors[0].Rule != tt.expected):
+				t.Errorf("checkValue(%s) = %v, expected %s", tt.value, fieldErrors, tt.expected)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@
 package goald
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/aldesgroup/goald/features/hstatus"
@@ -15,7 +16,7 @@ func GenericHandleCreate[BOTYPE IBusinessObject]() *oneForOneEndpoint[BOTYPE, BO
 		// new (anonym) handler function here
 		func(webCtx WebContext, input BOTYPE) (BOTYPE, hstatus.Code, string) {
 			if errCreate := CreateBO(webCtx.GetBloContext(), input); errCreate != nil {
-				return *new(BOTYPE), hstatus.InternalServerError,
+				return *new(BOTYPE), getErrorStatus(webCtx, errCreate),
 					fmt.Sprintf("Failed creating a new '%T' instance: %s", input, errCreate)
 			}
 
@@ -24,6 +25,8 @@ func GenericHandleCreate[BOTYPE IBusinessObject]() *oneForOneEndpoint[BOTYPE, BO
 		// passing the loading type
 		"")
 
+	ep.genericWrite = true
+
 	return ep
 }
 
@@ -32,9 +35,9 @@ func GenericHandleRead[BOTYPE IBusinessObject](idProp IField, loadingType Loadin
 		// new (anonym) handler function here
 		func(webCtx WebContext) (BOTYPE, hstatus.Code, string) {
 			// boClass := GetClass[BOTYPE]()
-			output, errRead := ReadBO(webCtx.GetBloContext(), idProp, webCtx.GetTargetRefOrID(), loadingType)
+			output, errRead := ReadBO(webCtx.GetBloContext(), idProp, webCtx.GetTargetRefOrID(), loadingType, webCtx.GetSelectedFields()...)
 			if errRead != nil {
-				return *new(BOTYPE), hstatus.InternalServerError,
+				return *new(BOTYPE), getErrorStatus(webCtx, errRead),
 					fmt.Sprintf("Failed reading '%s' instance '%s': %s", idProp.ownerSpecs().base().name, webCtx.GetTargetRefOrID(), errRead)
 			}
 
@@ -53,7 +56,7 @@ func GenericHandleUpdate[BOTYPE IBusinessObject](loadingType LoadingType) *oneFo
 		// new (anonym) handler function here
 		func(webCtx WebContext, input BOTYPE) (BOTYPE, hstatus.Code, string) {
 			if errUpdate := UpdateBO(webCtx.GetBloContext(), input, loadingType); errUpdate != nil {
-				return *new(BOTYPE), hstatus.InternalServerError,
+				return *new(BOTYPE), getErrorStatus(webCtx, errUpdate),
 					fmt.Sprintf("Failed creating a new '%T' instance: %s", input, errUpdate)
 			}
 
@@ -62,6 +65,30 @@ func GenericHandleUpdate[BOTYPE IBusinessObject](loadingType LoadingType) *oneFo
 		// passing the loading type
 		loadingType)
 
+	ep.genericWrite = true
+
+	return ep
+}
+
+func GenericHandlePatch[BOTYPE IBusinessObject](idProp IField, loadingType LoadingType) *oneForPatchEndpoint[BOTYPE] {
+	ep := PatchOne[BOTYPE](
+		// new (anonym) handler function here
+		func(webCtx WebContext, patch Patch) (BOTYPE, hstatus.Code, string) {
+			output, errPatch := PatchBO(webCtx.GetBloContext(), idProp, webCtx.GetTargetRefOrID(), patch)
+			if errPatch != nil {
+				return *new(BOTYPE), getErrorStatus(webCtx, errPatch),
+					fmt.Sprintf("Failed patching '%s' instance '%s': %s", idProp.ownerSpecs().base().name, webCtx.GetTargetRefOrID(), errPatch)
+			}
+
+			return output.(BOTYPE), hstatus.OK, fmt.Sprintf("Patched the targeted '%T' instance", output)
+		},
+		// passing the loading type
+		loadingType)
+
+	ep.TargetWith(idProp)
+
+	ep.genericWrite = true
+
 	return ep
 }
 
@@ -70,10 +97,10 @@ func GenericHandleDelete[BOTYPE IBusinessObject](idProp IField) *oneForNoneEndpo
 		// new (anonym) handler function here
 		func(webCtx WebContext) (BOTYPE, hstatus.Code, string) {
 			// boClass := GetClass[BOTYPE]()
-			output, errRead := DeleteBO(webCtx.GetBloContext(), idProp, webCtx.GetTargetRefOrID())
-			if errRead != nil {
-				return *new(BOTYPE), hstatus.InternalServerError,
-					fmt.Sprintf("Failed reading '%s' instance '%s': %s", idProp.ownerSpecs().base().name, webCtx.GetTargetRefOrID(), errRead)
+			output, errDelete := DeleteBO(webCtx.GetBloContext(), idProp, webCtx.GetTargetRefOrID())
+			if errDelete != nil {
+				return *new(BOTYPE), getErrorStatus(webCtx, errDelete),
+					fmt.Sprintf("Failed deleting '%s' instance '%s': %s", idProp.ownerSpecs().base().name, webCtx.GetTargetRefOrID(), errDelete)
 			}
 
 			return output.(BOTYPE), hstatus.OK, fmt.Sprintf("Deleted the targeted '%T' instance", output)
@@ -81,5 +108,136 @@ func GenericHandleDelete[BOTYPE IBusinessObject](idProp IField) *oneForNoneEndpo
 
 	ep.TargetWith(idProp)
 
+	ep.genericWrite = true
+
+	return ep
+}
+
+// the action path of the generic endpoints creating or updating many BOs at once, not to collide with the ones handling 1 BO,
+// e.g. "POST /orders/bulk" vs "POST /orders"; the bulk deletion is served on the collection path, e.g. "DELETE /orders",
+// since a static segment would collide with the wildcard of the single deletion, e.g. "DELETE /orders/:ID"
+const bulkACTIONxPATH = "bulk"
+
+func GenericHandleCreateMany[BOTYPE IBusinessObject]() *manyForManyEndpoint[BOTYPE, BOTYPE] {
+	ep := PostManyGetMany[BOTYPE](
+		// new (anonym) handler function here
+		func(webCtx WebContext, inputs []BOTYPE) ([]BOTYPE, hstatus.Code, string) {
+			if errCreate := CreateBOs(webCtx.GetBloContext(), webCtx.GetResource(), inputs); errCreate != nil {
+				return nil, getErrorStatus(webCtx, errCreate),
+					fmt.Sprintf("Failed creating %d new '%s' instances: %s", len(inputs), webCtx.GetResource().base().name, errCreate)
+			}
+
+			return inputs, hstatus.Created, fmt.Sprintf("Created %d new '%s' instances", len(inputs), webCtx.GetResource().base().name)
+		},
+		// passing the loading type
+		"")
+
+	ep.At(bulkACTIONxPATH)
+
+	ep.genericWrite = true
+
+	return ep
+}
+
+func GenericHandleUpdateMany[BOTYPE IBusinessObject](loadingType LoadingType) *manyForManyEndpoint[BOTYPE, BOTYPE] {
+	ep := PutMany[BOTYPE](
+		// new (anonym) handler function here
+		func(webCtx WebContext, inputs []BOTYPE) ([]BOTYPE, hstatus.Code, string) {
+			if errUpdate := UpdateBOs(webCtx.GetBloContext(), webCtx.GetResource(), inputs); errUpdate != nil {
+				return nil, getErrorStatus(webCtx, errUpdate),
+					fmt.Sprintf("Failed updating %d '%s' instances: %s", len(inputs), webCtx.GetResource().base().name, errUpdate)
+			}
+
+			return inputs, hstatus.OK, fmt.Sprintf("Updated the %d given '%s' instances", len(inputs), webCtx.GetResource().base().name)
+		},
+		// passing the loading type
+		loadingType)
+
+	ep.At(bulkACTIONxPATH)
+
+	ep.genericWrite = true
+
+	return ep
+}
+
+func GenericHandleDeleteMany[BOTYPE IBusinessObject]() *manyForManyEndpoint[BOTYPE, BOTYPE] {
+	ep := DeleteMany[BOTYPE](
+		// new (anonym) handler function here
+		func(webCtx WebContext, inputs []BOTYPE) ([]BOTYPE, hstatus.Code, string) {
+			outputs, errDelete := DeleteBOs(webCtx.GetBloContext(), webCtx.GetResource(), inputs)
+			if errDelete != nil {
+				return nil, getErrorStatus(webCtx, errDelete),
+					fmt.Sprintf("Failed deleting %d '%s' instances: %s", len(inputs), webCtx.GetResource().base().name, errDelete)
+			}
+
+			return outputs, hstatus.OK, fmt.Sprintf("Deleted the %d targeted '%s' instances", len(outputs), webCtx.GetResource().base().name)
+		})
+
+	ep.genericWrite = true
+
+	return ep
+}
+
+func GenericHandleUpload[BOTYPE IBusinessObject](attachmentField *AttachmentField, loadingType LoadingType) *oneForFileEndpoint[BOTYPE] {
+	ep := UploadFile[BOTYPE](
+		// new (anonym) handler function here
+		func(webCtx WebContext, file *AttachedFile) (BOTYPE, hstatus.Code, string) {
+			output, errAttach := AttachFile(webCtx.GetBloContext(), attachmentField, webCtx.GetTargetRefOrID(), file)
+			if errAttach != nil {
+				return *new(BOTYPE), getErrorStatus(webCtx, errAttach),
+					fmt.Sprintf("Failed attaching file '%s' to '%s' instance '%s': %s", file.Name,
+						attachmentField.ownerSpecs().base().name, webCtx.GetTargetRefOrID(), errAttach)
+			}
+
+			return output.(BOTYPE), hstatus.OK, fmt.Sprintf("Attached file '%s' to the targeted '%T' instance", file.Name, output)
+		},
+		// passing the loading type
+		loadingType)
+
+	// e.g. "/invoices/:ID/document", so as not to collide with the generic read, e.g. "/invoices/:ID"
+	ep.TargetWith(attachmentField.ownerSpecs().ID()).subPath = toKebabCase(attachmentField.getName())
+
+	ep.SetMaxUploadSize(attachmentField.maxSize)
+
+	ep.genericWrite = true
+
 	return ep
 }
+
+func GenericHandleDownload[BOTYPE IBusinessObject](attachmentField *AttachmentField) *fileForNoneEndpoint[BOTYPE] {
+	ep := DownloadFile[BOTYPE](
+		// new (anonym) handler function here
+		func(webCtx WebContext) (*AttachedFile, hstatus.Code, string) {
+			file, errOpen := OpenAttachedFile(webCtx.GetBloContext(), attachmentField, webCtx.GetTargetRefOrID())
+			if errOpen != nil {
+				return nil, hstatus.NotFound,
+					fmt.Sprintf("Failed getting the file attached to '%s' instance '%s': %s",
+						attachmentField.ownerSpecs().base().name, webCtx.GetTargetRefOrID(), errOpen)
+			}
+
+			return file, hstatus.OK, ""
+		})
+
+	// e.g. "/invoices/:ID/document", so as not to collide with the generic read, e.g. "/invoices/:ID"
+	ep.TargetWith(attachmentField.ownerSpecs().ID()).subPath = toKebabCase(attachmentField.getName())
+
+	return ep
+}
+
+// returns the HTTP status corresponding to the given error; if it's due to the input not complying with
+// the specs, then we answer with a bad request status, and all the faulty fields in the response details;
+// if the targeted BO does not exist, then it's a not found status
+func getErrorStatus(webCtx WebContext, err error) hstatus.Code {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		webCtx.SetResponseDetails(validationErr.FieldErrors)
+		return hstatus.BadRequest
+	}
+
+	var notFoundErr *NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return hstatus.NotFound
+	}
+
+	return hstatus.InternalServerError
+}
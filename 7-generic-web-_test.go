@@ -0,0 +1,59 @@
+package goald
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGenericSingleAndBulkRoutes(t *testing.T) {
+	useTestEndpoints(t)
+	orderSpecs, _, _ := registerRouteTestClasses(t)
+
+	GenericHandleCreate[*routeTestOrder]()
+	GenericHandleRead[*routeTestOrder](orderSpecs.ID(), "")
+	GenericHandleUpdate[*routeTestOrder]("")
+	GenericHandlePatch[*routeTestOrder](orderSpecs.ID(), "")
+	GenericHandleDelete[*routeTestOrder](orderSpecs.ID())
+	GenericHandleCreateMany[*routeTestOrder]()
+	GenericHandleUpdateMany[*routeTestOrder]("")
+	GenericHandleDeleteMany[*routeTestOrder]()
+
+	router := mountTestEndpoints(t)
+
+	tests := []struct {
+		method         string
+		path           string
+		expectedParams map[string]string
+	}{
+		{http.MethodPost, "/api/orders", nil},
+		{http.MethodGet, "/api/orders/12", map[string]string{"ID": "12"}},
+		{http.MethodPut, "/api/orders", nil},
+		{http.MethodPatch, "/api/orders/12", map[string]string{"ID": "12"}},
+		{http.MethodDelete, "/api/orders/12", map[string]string{"ID": "12"}},
+		{http.MethodPost, "/api/orders/bulk", nil},
+		{http.MethodPut, "/api/orders/bulk", nil},
+		{http.MethodDelete, "/api/orders", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			checkTestRoute(t, router, tt.method, tt.path, tt.expectedParams)
+		})
+	}
+}
+
+func TestGenericReadAndAttachmentRoutes(t *testing.T) {
+	useTestEndpoints(t)
+	orderSpecs, _, _ := registerRouteTestClasses(t)
+	scannedDocument := NewAttachmentField(orderSpecs, "ScannedDocument", false)
+
+	GenericHandleRead[*routeTestOrder](orderSpecs.ID(), "")
+	GenericHandleUpload[*routeTestOrder](scannedDocument, "")
+	GenericHandleDownload[*routeTestOrder](scannedDocument)
+
+	router := mountTestEndpoints(t)
+
+	checkTestRoute(t, router, http.MethodGet, "/api/orders/12", map[string]string{"ID": "12"})
+	checkTestRoute(t, router, http.MethodPost, "/api/orders/12/scanned-document", map[string]string{"ID": "12"})
+	checkTestRoute(t, router, http.MethodGet, "/api/orders/12/scanned-document", map[string]string{"ID": "12"})
+}
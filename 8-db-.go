@@ -48,6 +48,43 @@ func (thisDB *DB) Exec(query string, args ...any) (sql.Result, error) {
 	return thisDB.DB.Exec(query, args...)
 }
 
+// ------------------------------------------------------------------------------------------------
+// Transactions
+// ------------------------------------------------------------------------------------------------
+
+// what's common to a DB and a transaction, to run our queries
+type dbExecutor interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// goald's own transaction object
+type dbTransaction struct {
+	*sql.Tx
+	db *DB
+}
+
+func (thisDB *DB) begin() (*dbTransaction, error) {
+	tx, errBegin := thisDB.DB.Begin()
+	if errBegin != nil {
+		return nil, errBegin
+	}
+
+	return &dbTransaction{Tx: tx, db: thisDB}, nil
+}
+
+// proxying this function so as to add functionality
+func (thisTx *dbTransaction) Query(query string, args ...any) (*sql.Rows, error) {
+	defer logSQL(time.Now(), query, args...)
+	return thisTx.Tx.Query(query, args...)
+}
+
+// proxying this function so as to add functionality
+func (thisTx *dbTransaction) Exec(query string, args ...any) (sql.Result, error) {
+	defer logSQL(time.Now(), query, args...)
+	return thisTx.Tx.Exec(query, args...)
+}
+
 // ------------------------------------------------------------------------------------------------
 // Opening a DB, checking it, etc.
 // ------------------------------------------------------------------------------------------------
@@ -78,7 +115,8 @@ func openDB(conf *dbConfig) (*sql.DB, iDBAdapter) {
 	}
 
 	// Pinging
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 	if errPing := db.PingContext(ctx); errPing != nil {
 		core.PanicMsg("Issue while testing the '%s' DB: %s", conf.DbID, errPing)
 	}
@@ -4,5 +4,43 @@ package goald
 type iDBAdapter interface {
 	getConnectionString(conf *dbConfig) string
 	getTablesQuery(dbName string) string
-	getSQLColumnDeclaration(property iBusinessObjectProperty) string
+	getSQLColumnDeclaration(property iBusinessObjectProperty, sharedTable bool) string
+	getSQLDiscriminatorColumnDeclaration() string
+	getSQLIDColumnDeclaration(columnName string, idStrategy IDStrategy) string
+	getSQLChildValuesColumnDeclarations(ownerIDStrategy IDStrategy, field IField) []string
+	getSQLIdempotencyColumnDeclarations() []string
+	getCreateIndexQuery(tableName string, column string) string
+	getCreateUniqueIndexQuery(tableName string, property iBusinessObjectProperty, sharedTable bool) string
+
+	// CRUD operations
+	getSelectQuery(tableName string, columns []string, whereColumn string, nbValues int) string
+	getLockQuery(tableName string, whereColumn string) string
+	getDeleteQuery(tableName string, whereColumn string, nbValues int) string
+	getDeleteBeforeQuery(tableName string, dateColumn string, otherColumns ...string) string
+	getJSONPathSelectQuery(tableName string, columns []string, jsonColumn string) string
+	getFilteredTableSource(tableName string, column string, values []string) string
+
+	// bulk operations
+	getMaxParamsPerQuery() int
+	getInsertManyQuery(tableName string, idColumn string, columns []string, nbRows int) string
+	getInsertValuesQuery(tableName string, columns []string, nbRows int) string
+	getUpdateManyQuery(tableName string, columns []string, nbRows int) string
+	getUpsertManyQuery(tableName string, idColumn string, keyColumn string, columns []string, nbRows int) string
 }
+
+// the columns of the tables containing the values of multiple fields, 1 row per value
+const (
+	childValuesOWNERxID = "owner_id" // the ID of the BO owning the value
+	childValuesPOSITION = "position" // the position of the value within the field's values
+	childValuesVALUE    = "value"    // the value itself
+)
+
+// the table where goald keeps the responses of the idempotent requests, and its columns
+const (
+	idempotencyTABLE       = "goald_idempotency_keys"
+	idempotencyID          = "id"          // the request's route, along with the key given by the client
+	idempotencyFINGERPRINT = "fingerprint" // a hash of the request's method, URL & body
+	idempotencySTATUS      = "status_code" // the response's HTTP status; 0 while the request is being handled
+	idempotencyRESPONSE    = "response"    // the response's JSON body
+	idempotencyCREATION    = "created_at"  // when the request was 1st received
+)
@@ -3,8 +3,12 @@ package goald
 import (
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
 
 	core "github.com/aldesgroup/corego"
+	"github.com/aldesgroup/goald/features/utils"
 )
 
 // specific queries for SQL Server databases
@@ -22,35 +26,306 @@ func (thisAdapter *dbAdapterMSSQL) getTablesQuery(dbName string) string {
 	return fmt.Sprintf("SELECT name from %s.sys.tables", dbName)
 }
 
-// getSQLColumnDeclaration returns the type of the column to create for the given BO property
-func (thisAdapter *dbAdapterMSSQL) getSQLColumnDeclaration(property iBusinessObjectProperty) string {
-	notNull := core.IfThenElse(property.isMandatory(), " NOT NULL", "")
+// in a table shared by several classes, a column cannot be NOT NULL, since it's not used by all the rows
+func isNotNullColumn(property iBusinessObjectProperty, sharedTable bool) bool {
+	return property.isMandatory() && !sharedTable
+}
+
+// getSQLColumnDeclaration returns the declaration of the column to create for the given BO property; a nullable
+// column cannot be declared UNIQUE, since MSSQL would then accept only one NULL value: it's indexed instead
+func (thisAdapter *dbAdapterMSSQL) getSQLColumnDeclaration(property iBusinessObjectProperty, sharedTable bool) string {
+	notNull := isNotNullColumn(property, sharedTable)
+	constraints := core.IfThenElse(notNull, " NOT NULL", "")
+	constraints += core.IfThenElse(property.isUnique() && notNull, " UNIQUE", "")
+
+	// booleans do not bear any constraint
+	if _, isBool := property.(*BoolField); isBool && !property.isMultiple() {
+		constraints = ""
+	}
+
+	if field, isField := property.(IField); isField && field.isMultiple() {
+		// several enum values are stored in a delimited column, e.g. "1,3"
+		if field.getTypeFamily() == utils.TypeFamilyENUM {
+			return property.getColumnName() + " VARCHAR(255)" + constraints
+		}
+
+		// the other multiple fields have their values serialized as a JSON array
+		return property.getColumnName() + " NVARCHAR(MAX)" + constraints
+	}
+
+	if columnType := thisAdapter.getSQLColumnType(property); columnType != "" {
+		return property.getColumnName() + " " + columnType + constraints
+	}
+
+	slog.Error(fmt.Sprintf("Not handling this property in DB: %s", property.getName()))
 
+	return ""
+}
+
+// getSQLColumnType returns the SQL type able to contain 1 value of the given BO property
+func (thisAdapter *dbAdapterMSSQL) getSQLColumnType(property iBusinessObjectProperty) string {
 	switch property := property.(type) {
 	case *Relationship:
-		return property.getColumnName() + " BIGINT" + notNull
+		// the column must be able to contain the ID of the targeted class - all the targets of a polymorphic one share the same ID strategy
+		return thisAdapter.getIDColumnType(property.targets[0].getIDStrategy())
 	case *BoolField:
-		return property.getColumnName() + " BIT"
+		return "BIT"
 	case *StringField:
-		return property.getColumnName() + fmt.Sprintf(" VARCHAR(%d)", property.size) + notNull
+		return fmt.Sprintf("VARCHAR(%d)", property.size)
 	case *IntField:
-		return property.getColumnName() + " INT" + notNull
+		return "INT"
 	case *BigIntField:
-		return property.getColumnName() + " BIGINT" + notNull
+		return "BIGINT"
 	case *RealField:
-		return property.getColumnName() + " REAL" + notNull
+		return "REAL"
 	case *DoubleField:
-		return property.getColumnName() + " FLOAT" + notNull
+		return "FLOAT"
+	case *DecimalField:
+		return fmt.Sprintf("DECIMAL(%d,%d)", property.precision, property.scale)
 	case *DateField:
-		return property.getColumnName() + " DATETIME2(6)" + notNull
+		return "DATETIME2(6)"
+	case *DateOnlyField:
+		return "DATE"
+	case *TimeOfDayField:
+		return "TIME(0)"
+	case *DurationField:
+		// a number of nanoseconds
+		return "BIGINT"
+	case *JSONField:
+		return "NVARCHAR(MAX)"
+	case *BinaryField:
+		// VARBINARY(N) cannot go beyond 8000 bytes
+		if property.maxSize > 0 && property.maxSize <= 8000 {
+			return fmt.Sprintf("VARBINARY(%d)", property.maxSize)
+		}
+		return "VARBINARY(MAX)"
+	case *AttachmentField:
+		// only the attachment's metadata are stored here, as JSON
+		return "NVARCHAR(1000)"
 	case *EnumField:
-		if property.isMultiple() {
-			panic("not handling listenums yet!!!")
+		return "INT"
+	}
+
+	return ""
+}
+
+// getSQLDiscriminatorColumnDeclaration returns the declaration of the column containing the class names,
+// in the tables shared by several classes
+func (thisAdapter *dbAdapterMSSQL) getSQLDiscriminatorColumnDeclaration() string {
+	return discriminatorCOLUMN + " VARCHAR(255) NOT NULL"
+}
+
+// getSQLChildValuesColumnDeclarations returns the declarations of the columns of the table containing
+// the values of the given multiple field, 1 row per value
+func (thisAdapter *dbAdapterMSSQL) getSQLChildValuesColumnDeclarations(ownerIDStrategy IDStrategy, field IField) []string {
+	return []string{
+		childValuesOWNERxID + " " + thisAdapter.getIDColumnType(ownerIDStrategy) + " NOT NULL",
+		childValuesPOSITION + " INT NOT NULL",
+		childValuesVALUE + " " + thisAdapter.getSQLColumnType(field),
+	}
+}
+
+// getSQLIdempotencyColumnDeclarations returns the declarations of the columns of the table containing the responses
+// of the idempotent requests
+func (thisAdapter *dbAdapterMSSQL) getSQLIdempotencyColumnDeclarations() []string {
+	return []string{
+		idempotencyID + " NVARCHAR(450) NOT NULL PRIMARY KEY",
+		idempotencyFINGERPRINT + " CHAR(64) NOT NULL",
+		idempotencySTATUS + " INT NOT NULL",
+		idempotencyRESPONSE + " NVARCHAR(MAX)",
+		idempotencyCREATION + " DATETIME2 NOT NULL",
+	}
+}
+
+// the type of the columns containing IDs - whether for the primary key or the relationships
+func (thisAdapter *dbAdapterMSSQL) getIDColumnType(idStrategy IDStrategy) string {
+	if idStrategy.isUUID() {
+		return "CHAR(36)"
+	}
+
+	return "BIGINT"
+}
+
+// getSQLIDColumnDeclaration returns the declaration of the primary key column of a BO class' table
+func (thisAdapter *dbAdapterMSSQL) getSQLIDColumnDeclaration(columnName string, idStrategy IDStrategy) string {
+	if idStrategy.isUUID() {
+		return columnName + " " + thisAdapter.getIDColumnType(idStrategy) + " NOT NULL PRIMARY KEY"
+	}
+
+	return columnName + " " + thisAdapter.getIDColumnType(idStrategy) + " IDENTITY(1,1) PRIMARY KEY"
+}
+
+// ------------------------------------------------------------------------------------------------
+// CRUD operations
+// ------------------------------------------------------------------------------------------------
+
+// returns the "@p1, @p2, ..." list of the parameters from the given number, to the given number, included
+func (thisAdapter *dbAdapterMSSQL) getParams(from, to int) string {
+	params := []string{}
+	for paramNum := from; paramNum <= to; paramNum++ {
+		params = append(params, fmt.Sprintf("@p%d", paramNum))
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// if a where column is given, then the rows are filtered on the given number of values for this column
+func (thisAdapter *dbAdapterMSSQL) getSelectQuery(tableName string, columns []string, whereColumn string, nbValues int) string {
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), tableName)
+
+	if whereColumn != "" {
+		if nbValues == 1 {
+			query += fmt.Sprintf(" WHERE %s = @p1", whereColumn)
+		} else {
+			query += fmt.Sprintf(" WHERE %s IN (%s)", whereColumn, thisAdapter.getParams(1, nbValues))
 		}
-		return property.getColumnName() + " INT" + notNull
 	}
 
-	slog.Error(fmt.Sprintf("Not handling this property in DB: %s", property.getName()))
+	return query
+}
 
-	return ""
+// locks the rows having the given value for the given column, until the end of the current transaction
+func (thisAdapter *dbAdapterMSSQL) getLockQuery(tableName string, whereColumn string) string {
+	return fmt.Sprintf("SELECT 1 FROM %s WITH (UPDLOCK, ROWLOCK, HOLDLOCK) WHERE %s = @p1", tableName, whereColumn)
+}
+
+// the rows are filtered on the given number of values for the given column
+func (thisAdapter *dbAdapterMSSQL) getDeleteQuery(tableName string, whereColumn string, nbValues int) string {
+	if nbValues == 1 {
+		return fmt.Sprintf("DELETE FROM %s WHERE %s = @p1", tableName, whereColumn)
+	}
+
+	return fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", tableName, whereColumn, thisAdapter.getParams(1, nbValues))
+}
+
+// the rows having a date before the given one - the 1st param - in the given column are deleted; they can also be
+// filtered on the values - the next params - of the other given columns
+func (thisAdapter *dbAdapterMSSQL) getDeleteBeforeQuery(tableName string, dateColumn string, otherColumns ...string) string {
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s < @p1", tableName, dateColumn)
+	for i, column := range otherColumns {
+		query += fmt.Sprintf(" AND %s = @p%d", column, i+2)
+	}
+
+	return query
+}
+
+// an index on the given column of the given table
+func (thisAdapter *dbAdapterMSSQL) getCreateIndexQuery(tableName string, column string) string {
+	return fmt.Sprintf("CREATE INDEX ix_%s_%s ON %s (%s)", tableName, column, tableName, column)
+}
+
+// a unique index on the column of the given property, ignoring the NULL values, if the column has not been declared
+// UNIQUE already - i.e. if it's nullable
+func (thisAdapter *dbAdapterMSSQL) getCreateUniqueIndexQuery(tableName string, property iBusinessObjectProperty, sharedTable bool) string {
+	if _, isBool := property.(*BoolField); isBool || !property.isUnique() || isNotNullColumn(property, sharedTable) {
+		return ""
+	}
+
+	column := property.getColumnName()
+
+	return fmt.Sprintf("CREATE UNIQUE INDEX ux_%s_%s ON %s (%s) WHERE %s IS NOT NULL", tableName, column, tableName, column, column)
+}
+
+// the rows are filtered on the value found at a given path - the 1st param - within a JSON column - the 2nd param
+func (thisAdapter *dbAdapterMSSQL) getJSONPathSelectQuery(tableName string, columns []string, jsonColumn string) string {
+	return fmt.Sprintf("SELECT %s FROM %s WHERE JSON_VALUE(%s, @p1) = @p2", strings.Join(columns, ", "), tableName, jsonColumn)
+}
+
+// a part of the given table, to select from, restricted to the rows having one of the given values in the given column;
+// the values are inlined, so they must be safe, e.g. class names
+func (thisAdapter *dbAdapterMSSQL) getFilteredTableSource(tableName string, column string, values []string) string {
+	quotedValues := core.MapFn(values, func(value string) string { return "'" + value + "'" })
+	if len(quotedValues) == 0 {
+		quotedValues = []string{"NULL"} // matching no row
+	}
+
+	return fmt.Sprintf("(SELECT * FROM %s WHERE %s IN (%s)) AS %s", tableName, column, strings.Join(quotedValues, ", "), tableName)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Bulk operations
+// ------------------------------------------------------------------------------------------------
+
+// SQL Server does not accept more than 2100 parameters per request; we keep a bit of margin here
+func (thisAdapter *dbAdapterMSSQL) getMaxParamsPerQuery() int {
+	return 2000
+}
+
+// builds the "(VALUES (...), (...)) AS src (...)" source used in our MERGE statements; if withRowNum is true,
+// then each row starts with its number, so we can map the OUTPUT rows back to the inserted BOs
+func (thisAdapter *dbAdapterMSSQL) getMergeSource(columns []string, nbRows int, withRowNum bool) string {
+	rows := make([]string, nbRows)
+	for rowNum := range nbRows {
+		params := thisAdapter.getParams(rowNum*len(columns)+1, (rowNum+1)*len(columns))
+		if withRowNum {
+			params = strconv.Itoa(rowNum) + ", " + params
+		}
+		rows[rowNum] = "(" + params + ")"
+	}
+
+	srcColumns := core.IfThenElse(withRowNum, append([]string{mergeROWxNUM}, columns...), columns)
+
+	return fmt.Sprintf("(VALUES %s) AS src (%s)", strings.Join(rows, ", "), strings.Join(srcColumns, ", "))
+}
+
+// the column name used to number the rows of a MERGE source
+const mergeROWxNUM = "goald_row_num"
+
+// returns the "src.col1, src.col2" list for the given columns
+func prefixColumns(prefix string, columns []string) string {
+	return strings.Join(core.MapFn(columns, func(column string) string { return prefix + column }), ", ")
+}
+
+// returns the "col1 = src.col1, col2 = src.col2" list for the given columns, minus the excluded ones
+func setColumnsFromSource(columns []string, excluded ...string) string {
+	sets := []string{}
+	for _, column := range columns {
+		if !slices.Contains(excluded, column) {
+			sets = append(sets, fmt.Sprintf("%s = src.%s", column, column))
+		}
+	}
+
+	return strings.Join(sets, ", ")
+}
+
+// a MERGE that never matches is the only way to insert N rows and get their IDs back in a reliable order,
+// since the OUTPUT clause of a plain INSERT does not guarantee any order
+func (thisAdapter *dbAdapterMSSQL) getInsertManyQuery(tableName string, idColumn string, columns []string, nbRows int) string {
+	return fmt.Sprintf("MERGE INTO %s USING %s ON 1 = 0"+
+		" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)"+
+		" OUTPUT $action, src.%s, INSERTED.%s;",
+		tableName, thisAdapter.getMergeSource(columns, nbRows, true),
+		strings.Join(columns, ", "), prefixColumns("src.", columns),
+		mergeROWxNUM, idColumn)
+}
+
+// a plain insert, for the tables with no ID, like the child values tables
+func (thisAdapter *dbAdapterMSSQL) getInsertValuesQuery(tableName string, columns []string, nbRows int) string {
+	rows := make([]string, nbRows)
+	for rowNum := range nbRows {
+		rows[rowNum] = "(" + thisAdapter.getParams(rowNum*len(columns)+1, (rowNum+1)*len(columns)) + ")"
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", tableName, strings.Join(columns, ", "), strings.Join(rows, ", "))
+}
+
+// here, the 1st column must be the ID column
+func (thisAdapter *dbAdapterMSSQL) getUpdateManyQuery(tableName string, columns []string, nbRows int) string {
+	return fmt.Sprintf("MERGE INTO %s AS tgt USING %s ON tgt.%s = src.%s"+
+		" WHEN MATCHED THEN UPDATE SET %s;",
+		tableName, thisAdapter.getMergeSource(columns, nbRows, false), columns[0], columns[0],
+		setColumnsFromSource(columns, columns[0]))
+}
+
+// the rows are matched with the existing ones through the key column, which should be unique;
+// if the ID column is given - i.e. when the IDs are not generated by the DB - it's only used for the inserts
+func (thisAdapter *dbAdapterMSSQL) getUpsertManyQuery(tableName string, idColumn string, keyColumn string, columns []string, nbRows int) string {
+	return fmt.Sprintf("MERGE INTO %s AS tgt USING %s ON tgt.%s = src.%s"+
+		" WHEN MATCHED THEN UPDATE SET %s"+
+		" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)"+
+		" OUTPUT $action, src.%s, INSERTED.%s;",
+		tableName, thisAdapter.getMergeSource(columns, nbRows, true), keyColumn, keyColumn,
+		setColumnsFromSource(columns, keyColumn, idColumn),
+		strings.Join(columns, ", "), prefixColumns("src.", columns),
+		mergeROWxNUM, idColumn)
 }
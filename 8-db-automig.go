@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	core "github.com/aldesgroup/corego"
@@ -62,6 +63,7 @@ func migrate(db *DB) {
 	slog.Debug(fmt.Sprintf("Existing tables: %+v\n", existingTables))
 
 	createMissingTables(db, existingSpecs, existingTables)
+	createMissingIdempotencyTable(db, existingTables)
 	// tableColumns := getTableColumns(dbContext)
 	// createMissingColumns(dbContext, tableColumns)
 	// createMissingForeignKeys(dbContext)
@@ -82,12 +84,27 @@ func createMissingTables(db *DB, existingSpecs map[className]IBusinessObjectSpec
 
 	// iterating over all the persisted classes on the given DB, and creating the missing tables if needed
 	for _, boSpecs := range existingSpecs {
+		// the views & tables of the read-only classes are not ours to manage,
+		// and the abstract classes have no table - but the ones with a single table hierarchy
+		if boSpecs.isReadOnly() || boSpecs.base().abstract && boSpecs.base().inheritance != InheritanceStrategySINGLExTABLE {
+			continue
+		}
+
 		// // the corresponding table is required!
 		// requiredTableNames = append(requiredTableNames, __REPLACE__Schema.GetTable(dbContext))
 
 		// adding the table if it does not exist yet
 		if !core.InSlice[string](existingTables, boSpecs.getTableName()) {
 			createMissingTable(db, boSpecs)
+			existingTables = append(existingTables, boSpecs.getTableName())
+		}
+
+		// same for the tables containing the values of some multiple fields
+		for _, field := range boSpecs.base().getChildTableFields() {
+			if !core.InSlice[string](existingTables, field.getChildTableName()) {
+				createMissingChildValuesTable(db, boSpecs, field)
+				existingTables = append(existingTables, field.getChildTableName())
+			}
 		}
 	}
 
@@ -126,22 +143,35 @@ func getTableNames(db *DB) []string {
 	return tables
 }
 
-// createMissingTable creates the missing table corresponding to the given BO class
+// createMissingTable creates the missing table corresponding to the given BO class; in a single table hierarchy,
+// this table contains the columns of all the concrete classes, plus the discriminator column
 func createMissingTable(db *DB, boSpecs IBusinessObjectSpecs) {
 	slog.Info(fmt.Sprintf("Creating the missing table: %s", boSpecs.getTableName()))
 
 	// we can manage these columns manually
-	columnsSQL := newline + `id INT IDENTITY(1,1) PRIMARY KEY`
+	columnsSQL := newline + db.adapter.getSQLIDColumnDeclaration(boSpecs.ID().getColumnName(), boSpecs.getIDStrategy())
+
+	// the properties persisted in this table
+	sharedTable := boSpecs.base().isInSingleTable()
+	properties := boSpecs.base().getPersistedProperties()
+	if sharedTable {
+		root := core.IfThenElse(boSpecs.base().inheritance == InheritanceStrategySINGLExTABLE, boSpecs.base(), boSpecs.base().getMappedHierarchyRoot())
+		properties = getUnionOfPersistedProperties(append([]IBusinessObjectSpecs{root}, root.getConcreteSubSpecs()...))
+	}
 
 	// adding a column for each property that is persisted in the given BO class's table
-	slog.Debug(fmt.Sprintf("nb properties: %d", len(boSpecs.base().getPersistedProperties())))
-	for i, property := range boSpecs.base().getPersistedProperties() {
+	slog.Debug(fmt.Sprintf("nb properties: %d", len(properties)))
+	for i, property := range properties {
 		// we avoid to treat the id column twice, since we've already added it just below
 		if i > 0 {
-			columnsSQL = columnsSQL + "," + newline + db.adapter.getSQLColumnDeclaration(property)
+			columnsSQL = columnsSQL + "," + newline + db.adapter.getSQLColumnDeclaration(property, sharedTable)
 		}
 	}
 
+	if sharedTable {
+		columnsSQL = columnsSQL + "," + newline + db.adapter.getSQLDiscriminatorColumnDeclaration()
+	}
+
 	// we obviously add a constraint on the ID, the primary key
 	// TODO use adapter here
 	// columnsSQL = columnsSQL + newline + fmt.Sprintf("CONSTRAINT pk__%s PRIMARY KEY CLUSTERED (id ASC)", boSpecs.getTableName())
@@ -158,6 +188,54 @@ func createMissingTable(db *DB, boSpecs IBusinessObjectSpecs) {
 		slog.Error(fmt.Sprintf("Error creating table %s: %s", boSpecs.getTableName(), errCreate))
 		os.Exit(1)
 	}
+
+	// the unique columns that could not be declared as such are indexed
+	for _, property := range properties {
+		if indexQuery := db.adapter.getCreateUniqueIndexQuery(boSpecs.getTableName(), property, sharedTable); indexQuery != "" {
+			if _, errIndex := db.Exec(indexQuery); errIndex != nil {
+				slog.Error(fmt.Sprintf("Error indexing table %s: %s", boSpecs.getTableName(), errIndex))
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+// createMissingChildValuesTable creates the table containing the values of the given multiple field,
+// 1 row per value, along with the owner's ID & the value's position
+func createMissingChildValuesTable(db *DB, boSpecs IBusinessObjectSpecs, field IField) {
+	slog.Info(fmt.Sprintf("Creating the missing table: %s", field.getChildTableName()))
+
+	columnsSQL := newline + strings.Join(db.adapter.getSQLChildValuesColumnDeclarations(boSpecs.getIDStrategy(), field), ","+newline)
+	createQuery := fmt.Sprintf("CREATE TABLE %s (%s"+newline+")", field.getChildTableName(), columnsSQL)
+
+	if _, errCreate := db.Exec(createQuery); errCreate != nil {
+		slog.Error(fmt.Sprintf("Error creating table %s: %s", field.getChildTableName(), errCreate))
+		os.Exit(1)
+	}
+}
+
+// createMissingIdempotencyTable creates the table where goald keeps the responses of the idempotent requests,
+// if some idempotent endpoints write into the given DB
+func createMissingIdempotencyTable(db *DB, existingTables []string) {
+	if core.InSlice[string](existingTables, idempotencyTABLE) || !hasIdempotentEndpoints(db) {
+		return
+	}
+
+	slog.Info(fmt.Sprintf("Creating the missing table: %s", idempotencyTABLE))
+
+	columnsSQL := newline + strings.Join(db.adapter.getSQLIdempotencyColumnDeclarations(), ","+newline)
+	createQuery := fmt.Sprintf("CREATE TABLE %s (%s"+newline+")", idempotencyTABLE, columnsSQL)
+
+	if _, errCreate := db.Exec(createQuery); errCreate != nil {
+		slog.Error(fmt.Sprintf("Error creating table %s: %s", idempotencyTABLE, errCreate))
+		os.Exit(1)
+	}
+
+	// the expired requests are regularly looked for
+	if _, errIndex := db.Exec(db.adapter.getCreateIndexQuery(idempotencyTABLE, idempotencyCREATION)); errIndex != nil {
+		slog.Error(fmt.Sprintf("Error indexing table %s: %s", idempotencyTABLE, errIndex))
+		os.Exit(1)
+	}
 }
 
 // // type tableColumnInfo helps us retrieve relevant info about the columns of our tables
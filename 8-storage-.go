@@ -0,0 +1,197 @@
+// ------------------------------------------------------------------------------------------------
+// The code here is about storing the content of the files attached to business objects
+// ------------------------------------------------------------------------------------------------
+package goald
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	core "github.com/aldesgroup/corego"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Attachments
+// ------------------------------------------------------------------------------------------------
+
+// Attachment holds the metadata of a file attached to a business object, which are persisted with it;
+// the file's content is kept in a file storage
+type Attachment struct {
+	Name        string // the original file name, e.g. "invoice.pdf"
+	Size        int64  // the number of bytes
+	ContentType string // the MIME type, e.g. "application/pdf"
+	Checksum    string // the SHA-256 of the content, as an hexadecimal string
+}
+
+// AttachedFile is an attachment along with its content, as uploaded or downloaded
+type AttachedFile struct {
+	*Attachment
+	Content io.ReadCloser
+}
+
+// ------------------------------------------------------------------------------------------------
+// File storages
+// ------------------------------------------------------------------------------------------------
+
+// IFileStorage is where the content of the attached files is stored, under a given key, like
+// "Invoice/Document/42"; it can be a local directory, an object storage, etc.
+type IFileStorage interface {
+	Save(key string, content io.Reader) (int64, error) // stores - or replaces - the content, and returns the number of bytes written
+	Open(key string) (io.ReadCloser, error)            // gives access to a stored content
+	Delete(key string) error                           // removes a stored content; no error if there's none
+}
+
+// the file storage used by the attachment fields that do not specify their own one
+var defaultFileStorage IFileStorage
+
+// SetDefaultFileStorage allows to choose where the attached files are stored, for all the attachment
+// fields but the ones specifying their own storage; this should be called before the server is started
+func SetDefaultFileStorage(storage IFileStorage) {
+	defaultFileStorage = storage
+}
+
+// the storage for the files attached through this field
+func (f *AttachmentField) getStorage() IFileStorage {
+	return core.IfThenElse(f.storage != nil, f.storage, defaultFileStorage)
+}
+
+// the key under which the file attached to the given BO is stored
+func (f *AttachmentField) getStorageKey(id BObjID) string {
+	return string(f.ownerSpecs().base().name) + "/" + f.getName() + "/" + string(id)
+}
+
+// the storages able to move a content from a key to another without copying it, like the local one
+type iFileMover interface {
+	Move(fromKey, toKey string) error // replaces the content stored under the 2nd key, if any
+}
+
+// moves the content stored under the 1st key to the 2nd one, replacing the content stored there, if any
+func moveStoredFile(storage IFileStorage, fromKey, toKey string) error {
+	if mover, canMove := storage.(iFileMover); canMove {
+		return mover.Move(fromKey, toKey)
+	}
+
+	content, errOpen := storage.Open(fromKey)
+	if errOpen != nil {
+		return errOpen
+	}
+
+	_, errSave := storage.Save(toKey, content)
+	if errClose := content.Close(); errSave == nil {
+		errSave = errClose
+	}
+	if errSave != nil {
+		return errSave
+	}
+
+	return storage.Delete(fromKey)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Local file storage
+// ------------------------------------------------------------------------------------------------
+
+// stores the files within a local directory, a key being a relative path in there
+type localFileStorage struct {
+	rootDir string
+}
+
+// NewLocalFileStorage returns a storage keeping the files in the given directory
+func NewLocalFileStorage(rootDir string) IFileStorage {
+	return &localFileStorage{rootDir: rootDir}
+}
+
+// returns the path of the file associated with the given key, which must stay within the root dir
+func (thisStorage *localFileStorage) getPath(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", Error("Invalid storage key '%s'", key)
+	}
+
+	return filepath.Join(thisStorage.rootDir, filepath.FromSlash(key)), nil
+}
+
+func (thisStorage *localFileStorage) Save(key string, content io.Reader) (int64, error) {
+	path, errPath := thisStorage.getPath(key)
+	if errPath != nil {
+		return 0, errPath
+	}
+
+	if errDir := os.MkdirAll(filepath.Dir(path), 0o755); errDir != nil {
+		return 0, ErrorC(errDir, "Could not create the directory for '%s'", key)
+	}
+
+	// writing into a temporary file first, so as not to lose the current content if anything goes wrong
+	tmpFile, errCreate := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if errCreate != nil {
+		return 0, ErrorC(errCreate, "Could not create a file for '%s'", key)
+	}
+
+	written, errCopy := io.Copy(tmpFile, content)
+	errClose := tmpFile.Close()
+	if errCopy == nil {
+		errCopy = errClose
+	}
+	if errCopy != nil {
+		_ = os.Remove(tmpFile.Name())
+		return 0, ErrorC(errCopy, "Could not write the content of '%s'", key)
+	}
+
+	if errRename := os.Rename(tmpFile.Name(), path); errRename != nil {
+		_ = os.Remove(tmpFile.Name())
+		return 0, ErrorC(errRename, "Could not store the content of '%s'", key)
+	}
+
+	return written, nil
+}
+
+func (thisStorage *localFileStorage) Open(key string) (io.ReadCloser, error) {
+	path, errPath := thisStorage.getPath(key)
+	if errPath != nil {
+		return nil, errPath
+	}
+
+	file, errOpen := os.Open(path)
+	if errOpen != nil {
+		return nil, ErrorC(errOpen, "Could not open the content of '%s'", key)
+	}
+
+	return file, nil
+}
+
+func (thisStorage *localFileStorage) Move(fromKey, toKey string) error {
+	fromPath, errFromPath := thisStorage.getPath(fromKey)
+	if errFromPath != nil {
+		return errFromPath
+	}
+
+	toPath, errToPath := thisStorage.getPath(toKey)
+	if errToPath != nil {
+		return errToPath
+	}
+
+	if errDir := os.MkdirAll(filepath.Dir(toPath), 0o755); errDir != nil {
+		return ErrorC(errDir, "Could not create the directory for '%s'", toKey)
+	}
+
+	if errRename := os.Rename(fromPath, toPath); errRename != nil {
+		return ErrorC(errRename, "Could not move the content of '%s' to '%s'", fromKey, toKey)
+	}
+
+	return nil
+}
+
+func (thisStorage *localFileStorage) Delete(key string) error {
+	path, errPath := thisStorage.getPath(key)
+	if errPath != nil {
+		return errPath
+	}
+
+	if errRemove := os.Remove(path); errRemove != nil && !errors.Is(errRemove, fs.ErrNotExist) {
+		return ErrorC(errRemove, "Could not delete the content of '%s'", key)
+	}
+
+	return nil
+}
@@ -2,7 +2,6 @@
 package class
 
 import (
-	core "github.com/aldesgroup/corego"
 	"github.com/aldesgroup/goald"
 	"github.com/aldesgroup/goald/features/i18n"
 )
@@ -11,7 +10,7 @@ import (
 func (thisClass *TranslationClass) GetValueAsString(bo goald.IBusinessObject, propertyName string) string {
 	switch propertyName {
 	case "ID":
-		return core.Int64ToString(int64(bo.(*i18n.Translation).ID))
+		return string(bo.(*i18n.Translation).ID)
 	case "Key":
 		return bo.(*i18n.Translation).Key
 	case "Lang":
@@ -29,7 +28,7 @@ func (thisClass *TranslationClass) GetValueAsString(bo goald.IBusinessObject, pr
 func (thisClass *TranslationClass) SetValueAsString(bo goald.IBusinessObject, propertyName string, valueAsString string) error {
 	switch propertyName {
 	case "ID":
-		bo.(*i18n.Translation).ID = goald.BObjID(core.StringToInt64(valueAsString, "(*i18n.Translation).ID"))
+		bo.(*i18n.Translation).ID = goald.BObjID(valueAsString)
 	case "Key":
 		bo.(*i18n.Translation).Key = valueAsString
 	case "Lang":
@@ -38,7 +37,27 @@ func (thisClass *TranslationClass) SetValueAsString(bo goald.IBusinessObject, pr
 		bo.(*i18n.Translation).Namespace = valueAsString
 	case "Value":
 		bo.(*i18n.Translation).Value = valueAsString
+	default:
+		return goald.Error("Unknown property: %T.%s", bo, propertyName)
 	}
 
-	return goald.Error("Unknown property: %T.%s", bo, propertyName)
+	return nil
+}
+
+// getting the number of BOs a multiple relationship points to, without using reflection
+func (thisClass *TranslationClass) GetNumberOf(bo goald.IBusinessObject, relationshipName string) int {
+	switch relationshipName {
+
+	default:
+		return 0
+	}
+}
+
+// setting the BOs a relationship points to, once they've been loaded, without using reflection
+func (thisClass *TranslationClass) SetRelated(bo goald.IBusinessObject, relationshipName string, related []goald.IBusinessObject) error {
+	switch relationshipName {
+
+	default:
+		return goald.Error("Unknown relationship: %T.%s", bo, relationshipName)
+	}
 }
@@ -25,7 +25,27 @@ func (thisClass *TranslationUrlParamsClass) SetValueAsString(bo goald.IBusinessO
 		bo.(*i18n.TranslationUrlParams).Key = valueAsString
 	case "Namespace":
 		bo.(*i18n.TranslationUrlParams).Namespace = valueAsString
+	default:
+		return goald.Error("Unknown property: %T.%s", bo, propertyName)
 	}
 
-	return goald.Error("Unknown property: %T.%s", bo, propertyName)
+	return nil
+}
+
+// getting the number of BOs a multiple relationship points to, without using reflection
+func (thisClass *TranslationUrlParamsClass) GetNumberOf(bo goald.IBusinessObject, relationshipName string) int {
+	switch relationshipName {
+
+	default:
+		return 0
+	}
+}
+
+// setting the BOs a relationship points to, once they've been loaded, without using reflection
+func (thisClass *TranslationUrlParamsClass) SetRelated(bo goald.IBusinessObject, relationshipName string, related []goald.IBusinessObject) error {
+	switch relationshipName {
+
+	default:
+		return goald.Error("Unknown relationship: %T.%s", bo, relationshipName)
+	}
 }
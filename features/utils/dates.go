@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Dates without any time, nor timezone, e.g. birthdays
+// ------------------------------------------------------------------------------------------------
+
+// DateOnlyLAYOUT is how a DateOnly is written out, e.g. "2024-12-31"
+const DateOnlyLAYOUT = time.DateOnly
+
+// DateOnly is a calendar date; its zero value means "no date"
+type DateOnly struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDateOnly returns the given date, normalised, i.e. Feb 30th gives Mar 1st or 2nd
+func NewDateOnly(year int, month time.Month, day int) DateOnly {
+	return DateOnlyOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOnlyOf returns the date part of the given time, in the time's location
+func DateOnlyOf(t time.Time) DateOnly {
+	year, month, day := t.Date()
+	return DateOnly{Year: year, Month: month, Day: day}
+}
+
+// ParseDateOnly reads a date written like "2024-12-31"; an empty string gives the zero value
+func ParseDateOnly(valueAsString string) (DateOnly, error) {
+	if valueAsString == "" {
+		return DateOnly{}, nil
+	}
+
+	t, errParse := time.Parse(DateOnlyLAYOUT, valueAsString)
+	if errParse != nil {
+		return DateOnly{}, errParse
+	}
+
+	return DateOnlyOf(t), nil
+}
+
+func (thisDate DateOnly) IsZero() bool {
+	return thisDate == DateOnly{}
+}
+
+// In returns the time at which this date starts, in the given location
+func (thisDate DateOnly) In(loc *time.Location) time.Time {
+	return time.Date(thisDate.Year, thisDate.Month, thisDate.Day, 0, 0, 0, 0, loc)
+}
+
+// Compare returns -1, 0 or 1, whether this date is before, the same as, or after the other one
+func (thisDate DateOnly) Compare(other DateOnly) int {
+	return thisDate.In(time.UTC).Compare(other.In(time.UTC))
+}
+
+func (thisDate DateOnly) String() string {
+	if thisDate.IsZero() {
+		return ""
+	}
+
+	return thisDate.In(time.UTC).Format(DateOnlyLAYOUT)
+}
+
+func (thisDate DateOnly) MarshalJSON() ([]byte, error) {
+	if thisDate.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(thisDate.String())
+}
+
+func (thisDate *DateOnly) UnmarshalJSON(data []byte) (err error) {
+	var valueAsString *string
+	if errUnmarshal := json.Unmarshal(data, &valueAsString); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	if valueAsString == nil {
+		*thisDate = DateOnly{}
+		return nil
+	}
+
+	*thisDate, err = ParseDateOnly(*valueAsString)
+
+	return
+}
+
+// ------------------------------------------------------------------------------------------------
+// Times of day, without any date, nor timezone, e.g. opening hours
+// ------------------------------------------------------------------------------------------------
+
+// TimeOfDayLAYOUT is how a TimeOfDay is written out, e.g. "08:30:00"
+const TimeOfDayLAYOUT = time.TimeOnly
+
+// TimeOfDay is a time within any day, to the second; it's valid from 00:00:00 to 23:59:59
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+// TimeOfDayOf returns the time part of the given time, in the time's location
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second()}
+}
+
+// ParseTimeOfDay reads a time written like "08:30:00", or "08:30"
+func ParseTimeOfDay(valueAsString string) (TimeOfDay, error) {
+	if valueAsString == "" {
+		return TimeOfDay{}, nil
+	}
+
+	t, errParse := time.Parse(TimeOfDayLAYOUT, valueAsString)
+	if errParse != nil {
+		var errShort error
+		if t, errShort = time.Parse("15:04", valueAsString); errShort != nil {
+			return TimeOfDay{}, errParse
+		}
+	}
+
+	return TimeOfDayOf(t), nil
+}
+
+// SinceMidnight returns the duration between the start of the day and this time
+func (thisTime TimeOfDay) SinceMidnight() time.Duration {
+	return time.Duration(thisTime.Hour)*time.Hour + time.Duration(thisTime.Minute)*time.Minute +
+		time.Duration(thisTime.Second)*time.Second
+}
+
+// On returns the time at this time of day, on the given date, in the given location
+func (thisTime TimeOfDay) On(date DateOnly, loc *time.Location) time.Time {
+	return time.Date(date.Year, date.Month, date.Day, thisTime.Hour, thisTime.Minute, thisTime.Second, 0, loc)
+}
+
+// Compare returns -1, 0 or 1, whether this time is before, the same as, or after the other one
+func (thisTime TimeOfDay) Compare(other TimeOfDay) int {
+	switch {
+	case thisTime.SinceMidnight() < other.SinceMidnight():
+		return -1
+	case thisTime.SinceMidnight() > other.SinceMidnight():
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (thisTime TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", thisTime.Hour, thisTime.Minute, thisTime.Second)
+}
+
+func (thisTime TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(thisTime.String())
+}
+
+func (thisTime *TimeOfDay) UnmarshalJSON(data []byte) (err error) {
+	var valueAsString string
+	if errUnmarshal := json.Unmarshal(data, &valueAsString); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	*thisTime, err = ParseTimeOfDay(valueAsString)
+
+	return
+}
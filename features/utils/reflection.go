@@ -13,6 +13,8 @@ import (
 	"reflect"
 	"runtime"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // ------------------------------------------------------------------------------------------------
@@ -106,6 +108,15 @@ func (t GoaldType) PkgPath() string {
 	return t.val.PkgPath()
 }
 
+// the type pointed to, for a pointer type, or else the type itself
+func (t GoaldType) Indirect() GoaldType {
+	if t.val.Kind() == reflect.Ptr {
+		return newType(t.val.Elem())
+	}
+
+	return t
+}
+
 // --- fields ----------------------------------------------------------------------------------
 
 func (f GoaldField) Type() GoaldType {
@@ -125,7 +136,18 @@ func (f GoaldField) Name() string {
 // ------------------------------------------------------------------------------------------------
 
 var (
-	typeTIMExPTR = TypeOf((*time.Time)(nil), false)
+	typeTIMExPTR    = TypeOf((*time.Time)(nil), false)
+	typeDECIMAL     = TypeOf((*decimal.Decimal)(nil), true)
+	typeDATExONLY   = TypeOf((*DateOnly)(nil), true)
+	typeTIMExOFxDAY = TypeOf((*TimeOfDay)(nil), true)
+	typeDURATION    = TypeOf((*time.Duration)(nil), true)
+)
+
+// the struct tag that allows to specify how a business object's property should be handled, e.g.:
+// Payload MyStruct `goald:"json"`
+const (
+	GoaldTAG      = "goald"
+	GoaldTAGxJSON = "json" // the property is stored as a JSON document
 )
 
 // ------------------------------------------------------------------------------------------------
@@ -143,7 +165,14 @@ const (
 	TypeFamilyBIGINT
 	TypeFamilyREAL
 	TypeFamilyDOUBLE
+	TypeFamilyDECIMAL
 	TypeFamilyDATE
+	TypeFamilyDATExONLY
+	TypeFamilyTIMExOFxDAY
+	TypeFamilyDURATION
+	TypeFamilyJSON
+	TypeFamilyBINARY
+	TypeFamilyATTACHMENT
 	TypeFamilyENUM
 	TypeFamilyRELATIONSHIPxMONOM
 	TypeFamilyRELATIONSHIPxPOLYM
@@ -157,7 +186,14 @@ var typeFamilies = map[int]string{
 	int(TypeFamilyBIGINT):             "bigint",
 	int(TypeFamilyREAL):               "real number",
 	int(TypeFamilyDOUBLE):             "real number 64",
+	int(TypeFamilyDECIMAL):            "decimal number",
 	int(TypeFamilyDATE):               "date",
+	int(TypeFamilyDATExONLY):          "date without time",
+	int(TypeFamilyTIMExOFxDAY):        "time of day",
+	int(TypeFamilyDURATION):           "duration",
+	int(TypeFamilyJSON):               "JSON document",
+	int(TypeFamilyBINARY):             "binary data",
+	int(TypeFamilyATTACHMENT):         "attached file",
 	int(TypeFamilyENUM):               "enum",
 	int(TypeFamilyRELATIONSHIPxMONOM): "relationship (monomorphic)",
 	int(TypeFamilyRELATIONSHIPxPOLYM): "relationship (polymorphic)",
@@ -183,7 +219,7 @@ func (thisProperty TypeFamily) IsRelationship() bool {
 }
 
 // GetTypeFamily returns the type family of a given structfield
-func GetTypeFamily(field GoaldField, iBoTypeFamily, enumTypeFamily GoaldType) (TypeFamily TypeFamily, multiple bool) {
+func GetTypeFamily(field GoaldField, iBoTypeFamily, enumTypeFamily, attachmentType GoaldType) (TypeFamily TypeFamily, multiple bool) {
 
 	// to debug - to comment/uncomment when needed
 	// if structField.Name == "Num" {
@@ -200,12 +236,23 @@ func GetTypeFamily(field GoaldField, iBoTypeFamily, enumTypeFamily GoaldType) (T
 		// getting the field kind
 		fieldKind := fieldType.val.Kind()
 
+		// detecting a JSON document, i.e. a map with string keys, or anything explicitly tagged as such
+		if field.val.Tag.Get(GoaldTAG) == GoaldTAGxJSON ||
+			fieldKind == reflect.Map && fieldType.val.Key().Kind() == reflect.String {
+			return TypeFamilyJSON, false
+		}
+
 		// handling the case where we have a slice in here
 		if fieldKind == reflect.Slice {
 			// what's in there?
 			innerSliceType := fieldType.Elem()
 			innerSliceKind := innerSliceType.val.Kind()
 
+			// detecting raw binary data, i.e. []byte
+			if innerSliceKind == reflect.Uint8 {
+				return TypeFamilyBINARY, false
+			}
+
 			// detecting an enum
 			if innerSliceType.Implements(enumTypeFamily) {
 				return TypeFamilyENUM, true
@@ -221,6 +268,27 @@ func GetTypeFamily(field GoaldField, iBoTypeFamily, enumTypeFamily GoaldType) (T
 				return TypeFamilyRELATIONSHIPxMONOM, true
 			}
 
+			// detecting the basic types here
+			switch innerSliceKind {
+			case reflect.Bool:
+				return TypeFamilyBOOL, true
+
+			case reflect.String:
+				return TypeFamilySTRING, true
+
+			case reflect.Int:
+				return TypeFamilyINT, true
+
+			case reflect.Int64:
+				return TypeFamilyBIGINT, true
+
+			case reflect.Float32:
+				return TypeFamilyREAL, true
+
+			case reflect.Float64:
+				return TypeFamilyDOUBLE, true
+			}
+
 		} else { // we have a single element here
 
 			// detecting an enum
@@ -233,6 +301,26 @@ func GetTypeFamily(field GoaldField, iBoTypeFamily, enumTypeFamily GoaldType) (T
 				return TypeFamilyDATE, false
 			}
 
+			// detecting a date with no time, a time with no date, or a duration
+			switch {
+			case fieldType.Equals(typeDATExONLY):
+				return TypeFamilyDATExONLY, false
+			case fieldType.Equals(typeTIMExOFxDAY):
+				return TypeFamilyTIMExOFxDAY, false
+			case fieldType.Equals(typeDURATION):
+				return TypeFamilyDURATION, false
+			}
+
+			// detecting the metadata of a file attached to the business object
+			if fieldType.Equals(attachmentType) {
+				return TypeFamilyATTACHMENT, false
+			}
+
+			// detecting an exact decimal number
+			if fieldType.Equals(typeDECIMAL) {
+				return TypeFamilyDECIMAL, false
+			}
+
 			// detecting the basic types here
 			switch fieldKind {
 			case reflect.Bool:
@@ -292,6 +380,11 @@ func (thisValue GoaldValue) GetFieldValue(fieldName string) any {
 	return field.Interface()
 }
 
+// returns the zero value of the items of the given slice field
+func (thisValue GoaldValue) GetFieldItemZeroValue(fieldName string) any {
+	return reflect.Zero(thisValue.val.FieldByName(fieldName).Type().Elem()).Interface()
+}
+
 // ------------------------------------------------------------------------------------------------
 // misc dynamic stuff using reflection
 // ------------------------------------------------------------------------------------------------